@@ -0,0 +1,36 @@
+// Package clientgo connects godo to the Kubernetes client-go library. It is a
+// separate module so that the godo module does not depend on client-go.
+package clientgo
+
+import (
+	"errors"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/client-go/rest"
+)
+
+// RestConfigFromCredentials returns a client-go REST config for the cluster
+// described by the credentials, as returned by
+// godo.KubernetesService.GetCredentials. The token and the client certificate
+// are copied as given; credentials usually carry one or the other.
+func RestConfigFromCredentials(c *godo.KubernetesClusterCredentials) (*rest.Config, error) {
+	if c == nil {
+		return nil, errors.New("credentials cannot be nil")
+	}
+	if c.Server == "" {
+		return nil, errors.New("credentials have no server")
+	}
+	if c.Token == "" && (len(c.ClientCertificateData) == 0 || len(c.ClientKeyData) == 0) {
+		return nil, errors.New("credentials have neither a token nor a client certificate and key")
+	}
+
+	return &rest.Config{
+		Host:        c.Server,
+		BearerToken: c.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   c.CertificateAuthorityData,
+			CertData: c.ClientCertificateData,
+			KeyData:  c.ClientKeyData,
+		},
+	}, nil
+}
@@ -0,0 +1,56 @@
+package clientgo
+
+import (
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestConfigFromCredentials_Token(t *testing.T) {
+	creds := &godo.KubernetesClusterCredentials{
+		Server:                   "https://bd5f5959-5e1e-4205-a714-a914373942af.k8s.ondigitalocean.com",
+		CertificateAuthorityData: []byte("fake-ca"),
+		Token:                    "some-token",
+	}
+
+	config, err := RestConfigFromCredentials(creds)
+	require.NoError(t, err)
+	assert.Equal(t, creds.Server, config.Host)
+	assert.Equal(t, "some-token", config.BearerToken)
+	assert.Equal(t, []byte("fake-ca"), config.TLSClientConfig.CAData)
+	assert.Empty(t, config.TLSClientConfig.CertData)
+	assert.Empty(t, config.TLSClientConfig.KeyData)
+}
+
+func TestRestConfigFromCredentials_ClientCertificate(t *testing.T) {
+	creds := &godo.KubernetesClusterCredentials{
+		Server:                   "https://bd5f5959-5e1e-4205-a714-a914373942af.k8s.ondigitalocean.com",
+		CertificateAuthorityData: []byte("fake-ca"),
+		ClientCertificateData:    []byte("fake-cert"),
+		ClientKeyData:            []byte("fake-key"),
+	}
+
+	config, err := RestConfigFromCredentials(creds)
+	require.NoError(t, err)
+	assert.Equal(t, creds.Server, config.Host)
+	assert.Empty(t, config.BearerToken)
+	assert.Equal(t, []byte("fake-ca"), config.TLSClientConfig.CAData)
+	assert.Equal(t, []byte("fake-cert"), config.TLSClientConfig.CertData)
+	assert.Equal(t, []byte("fake-key"), config.TLSClientConfig.KeyData)
+}
+
+func TestRestConfigFromCredentials_Invalid(t *testing.T) {
+	_, err := RestConfigFromCredentials(nil)
+	require.Error(t, err)
+
+	_, err = RestConfigFromCredentials(&godo.KubernetesClusterCredentials{Token: "some-token"})
+	require.Error(t, err)
+
+	_, err = RestConfigFromCredentials(&godo.KubernetesClusterCredentials{
+		Server:                "https://bd5f5959-5e1e-4205-a714-a914373942af.k8s.ondigitalocean.com",
+		ClientCertificateData: []byte("fake-cert"),
+	})
+	require.Error(t, err)
+}
@@ -30,6 +30,7 @@ const (
 	headerRateRemaining         = "RateLimit-Remaining"
 	headerRateReset             = "RateLimit-Reset"
 	headerRequestID             = "x-request-id"
+	headerIdempotencyKey        = "Idempotency-Key"
 	internalHeaderRetryAttempts = "X-Godo-Retry-Attempts"
 
 	defaultRetryMax     = 4
@@ -102,6 +103,14 @@ type Client struct {
 	// Optional retry values. Setting the RetryConfig.RetryMax value enables automatically retrying requests
 	// that fail with 429 or 500-level response codes using the go-retryablehttp client
 	RetryConfig RetryConfig
+
+	// Optional retries of rate limited Kubernetes read requests. See
+	// KubernetesReadRetryConfig.
+	KubernetesReadRetryConfig KubernetesReadRetryConfig
+
+	// Optional labels that protect node pools from DeleteNodePool. See
+	// WithKubernetesProtectedNodePoolLabels.
+	KubernetesProtectedNodePoolLabels map[string]string
 }
 
 // RetryConfig sets the values used for enabling retries and backoffs for
@@ -283,7 +292,7 @@ func NewClient(httpClient *http.Client) *Client {
 	c.ImageActions = &ImageActionsServiceOp{client: c}
 	c.Invoices = &InvoicesServiceOp{client: c}
 	c.Keys = &KeysServiceOp{client: c}
-	c.Kubernetes = &KubernetesServiceOp{client: c}
+	c.Kubernetes = &KubernetesServiceOp{client: c, now: time.Now}
 	c.LoadBalancers = &LoadBalancersServiceOp{client: c}
 	c.Monitoring = &MonitoringServiceOp{client: c}
 	c.OneClick = &OneClickServiceOp{client: c}
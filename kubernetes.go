@@ -5,11 +5,15 @@ import (
 	"context"
 	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,36 +28,91 @@ const (
 // See: https://docs.digitalocean.com/reference/api/api-reference/#tag/Kubernetes
 type KubernetesService interface {
 	Create(context.Context, *KubernetesClusterCreateRequest) (*KubernetesCluster, *Response, error)
+	CreateWithOptions(ctx context.Context, create *KubernetesClusterCreateRequest, opts *KubernetesClusterCreateOptions) (*KubernetesCluster, *Response, error)
+	CreateAndVerify(ctx context.Context, req *KubernetesClusterCreateRequest, probe func(*KubernetesClusterConfig) error, opts *KubernetesWaitOptions) (*KubernetesCluster, error)
+	EnsureCluster(ctx context.Context, spec *KubernetesClusterCreateRequest) (*KubernetesCluster, bool, *Response, error)
 	Get(context.Context, string) (*KubernetesCluster, *Response, error)
+	GetClusterByName(ctx context.Context, name string) (*KubernetesCluster, *Response, error)
 	GetUser(context.Context, string) (*KubernetesClusterUser, *Response, error)
 	GetUpgrades(context.Context, string) ([]*KubernetesVersion, *Response, error)
 	GetKubeConfig(context.Context, string) (*KubernetesClusterConfig, *Response, error)
 	GetKubeConfigWithExpiry(context.Context, string, int64) (*KubernetesClusterConfig, *Response, error)
+	GetKubeConfigWithOptions(ctx context.Context, clusterID string, opts *KubernetesKubeConfigOptions) (*KubernetesClusterConfig, *Response, error)
 	GetCredentials(context.Context, string, *KubernetesClusterCredentialsGetRequest) (*KubernetesClusterCredentials, *Response, error)
+	GetClusterSupportedFeatures(ctx context.Context, clusterID string) ([]string, *Response, error)
 	List(context.Context, *ListOptions) ([]*KubernetesCluster, *Response, error)
+	ListClustersAll(ctx context.Context, opts *ListOptions) ([]*KubernetesCluster, *Response, error)
+	ClustersIterator(ctx context.Context, opts *ListOptions) *KubernetesClusterIterator
+	ListUnhealthyClusters(ctx context.Context) ([]*KubernetesCluster, *Response, error)
+	ListClustersByTag(ctx context.Context, tag string, opts *ListOptions) ([]*KubernetesCluster, *Response, error)
 	Update(context.Context, string, *KubernetesClusterUpdateRequest) (*KubernetesCluster, *Response, error)
+	EnableHA(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error)
+	DisableHA(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error)
+	PauseAutoUpgrade(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error)
+	ResumeAutoUpgrade(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error)
+	SetClusterBackupMetadata(ctx context.Context, clusterID, tool string, at time.Time) (*KubernetesCluster, *Response, error)
 	Upgrade(context.Context, string, *KubernetesClusterUpgradeRequest) (*Response, error)
 	Delete(context.Context, string) (*Response, error)
 	DeleteSelective(context.Context, string, *KubernetesClusterDeleteSelectiveRequest) (*Response, error)
 	DeleteDangerous(context.Context, string) (*Response, error)
 	ListAssociatedResourcesForDeletion(context.Context, string) (*KubernetesAssociatedResources, *Response, error)
+	PreflightDangerousDelete(ctx context.Context, clusterID string) (*DangerousDeletePreflight, *Response, error)
+	GetClusterStatusMessages(ctx context.Context, clusterID string, req *KubernetesGetClusterStatusMessagesRequest) ([]*KubernetesClusterStatusMessage, *Response, error)
+	GetRecentClusterStatusMessages(ctx context.Context, clusterID string, n int) ([]*KubernetesClusterStatusMessage, *Response, error)
+	StreamClusterStatusMessages(ctx context.Context, clusterID string, opts *KubernetesWaitOptions) (<-chan *KubernetesClusterStatusMessage, <-chan error)
+	GetClusterHistory(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesClusterEvent, *Response, error)
 
 	CreateNodePool(ctx context.Context, clusterID string, req *KubernetesNodePoolCreateRequest) (*KubernetesNodePool, *Response, error)
+	CreateNodePoolWithinLimits(ctx context.Context, clusterID string, req *KubernetesNodePoolCreateRequest, limits *KubernetesLimits) (*KubernetesNodePool, *Response, error)
 	GetNodePool(ctx context.Context, clusterID, poolID string) (*KubernetesNodePool, *Response, error)
+	GetNodePoolStatus(ctx context.Context, clusterID, poolID string) (*KubernetesNodePoolStatus, *Response, error)
+	GetNodePoolTemplate(ctx context.Context, clusterID string, nodePoolName string) (*KubernetesNodePoolTemplate, *Response, error)
+	GetNodePoolTemplates(ctx context.Context, clusterID string) (map[string]*KubernetesNodePoolTemplate, error)
+	ClusterAllocatableResources(ctx context.Context, clusterID string) (*KubernetesNodePoolResources, *Response, error)
+	GetNode(ctx context.Context, clusterID, poolID, nodeID string) (*KubernetesNode, *Response, error)
 	ListNodePools(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesNodePool, *Response, error)
+	ListNodePoolsAll(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesNodePool, *Response, error)
+	ListAllNodes(ctx context.Context, clusterID string) ([]*KubernetesNodeWithPool, error)
+	ListNodePoolsForClusters(ctx context.Context, clusterIDs []string, concurrency int) (map[string][]*KubernetesNodePool, error)
+	ListUnderMinNodePools(ctx context.Context, clusterID string) ([]*KubernetesNodePool, *Response, error)
+	CurrentSurgeNodes(ctx context.Context, clusterID string) (int, *Response, error)
 	UpdateNodePool(ctx context.Context, clusterID, poolID string, req *KubernetesNodePoolUpdateRequest) (*KubernetesNodePool, *Response, error)
+	UpdateNodePoolWithRetry(ctx context.Context, clusterID, poolID string, mutate func(*KubernetesNodePool) *KubernetesNodePoolUpdateRequest, maxRetries int) (*KubernetesNodePool, *Response, error)
+	ScaleNodePool(ctx context.Context, clusterID, poolID string, count int) (*KubernetesNodePool, *Response, error)
+	AddTaint(ctx context.Context, clusterID, poolID string, t Taint) (*KubernetesNodePool, *Response, error)
+	RemoveTaint(ctx context.Context, clusterID, poolID string, key string) (*KubernetesNodePool, *Response, error)
+	MergeLabels(ctx context.Context, clusterID, poolID string, labels map[string]string) (*KubernetesNodePool, *Response, error)
+	RemoveLabels(ctx context.Context, clusterID, poolID string, keys ...string) (*KubernetesNodePool, *Response, error)
+	AddTags(ctx context.Context, clusterID, poolID string, tags ...string) (*KubernetesNodePool, *Response, error)
+	RemoveTags(ctx context.Context, clusterID, poolID string, tags ...string) (*KubernetesNodePool, *Response, error)
+	CanScaleNodePool(ctx context.Context, clusterID, poolID string, target int, limits *KubernetesLimits) (bool, string, *Response, error)
+	EnableAutoScale(ctx context.Context, clusterID, poolID string, min, max int) (*KubernetesNodePool, *Response, error)
+	DisableAutoScale(ctx context.Context, clusterID, poolID string) (*KubernetesNodePool, *Response, error)
 	// RecycleNodePoolNodes is DEPRECATED please use DeleteNode
 	// The method will be removed in godo 2.0.
 	RecycleNodePoolNodes(ctx context.Context, clusterID, poolID string, req *KubernetesNodePoolRecycleNodesRequest) (*Response, error)
 	DeleteNodePool(ctx context.Context, clusterID, poolID string) (*Response, error)
+	ForceDeleteNodePool(ctx context.Context, clusterID, poolID string) (*Response, error)
+	DeleteNodePools(ctx context.Context, clusterID string, poolIDs []string) (*Response, error)
+	ReconcileNodePools(ctx context.Context, clusterID string, desired []*KubernetesNodePool) (*NodePoolReconcileResult, *Response, error)
+	ReconcileNodePoolsWithOptions(ctx context.Context, clusterID string, desired []*KubernetesNodePool, opts *NodePoolReconcileOptions) (*NodePoolReconcileResult, *Response, error)
 	DeleteNode(ctx context.Context, clusterID, poolID, nodeID string, req *KubernetesNodeDeleteRequest) (*Response, error)
+	DrainAndDeleteNode(ctx context.Context, clusterID, poolID, nodeID string, opts *KubernetesWaitOptions) (*KubernetesNode, *Response, error)
 
 	GetOptions(context.Context) (*KubernetesOptions, *Response, error)
+	CachedOptions(ctx context.Context, ttl time.Duration) (*KubernetesOptions, error)
+	GetLimits(ctx context.Context) (*KubernetesLimits, *Response, error)
 	AddRegistry(ctx context.Context, req *KubernetesClusterRegistryRequest) (*Response, error)
 	RemoveRegistry(ctx context.Context, req *KubernetesClusterRegistryRequest) (*Response, error)
+	RegistryStatus(ctx context.Context, clusterID string) (string, *Response, error)
+	IsRegistryEnabled(ctx context.Context, clusterID string) (bool, *Response, error)
+	ListRegistryIntegratedClusters(ctx context.Context) ([]string, *Response, error)
 
 	RunClusterlint(ctx context.Context, clusterID string, req *KubernetesRunClusterlintRequest) (string, *Response, error)
+	RunClusterlintAndWait(ctx context.Context, clusterID string, req *KubernetesRunClusterlintRequest, opts *KubernetesWaitOptions) ([]*ClusterlintDiagnostic, *Response, error)
 	GetClusterlintResults(ctx context.Context, clusterID string, req *KubernetesGetClusterlintRequest) ([]*ClusterlintDiagnostic, *Response, error)
+	GetLatestClusterlintResults(ctx context.Context, clusterID string) ([]*ClusterlintDiagnostic, *Response, error)
+	GetClusterlintCatalog(ctx context.Context) (KubernetesClusterlintCatalog, *Response, error)
 }
 
 var _ KubernetesService = &KubernetesServiceOp{}
@@ -61,6 +120,24 @@ var _ KubernetesService = &KubernetesServiceOp{}
 // KubernetesServiceOp handles communication with Kubernetes methods of the DigitalOcean API.
 type KubernetesServiceOp struct {
 	client *Client
+
+	// optionsMu guards the options cached by CachedOptions and the fetch of
+	// them in flight, if any.
+	optionsMu        sync.Mutex
+	options          *KubernetesOptions
+	optionsExpiresAt time.Time
+	optionsFetch     *kubernetesOptionsFetch
+
+	// now returns the current time; it is replaced in tests.
+	now func() time.Time
+}
+
+// kubernetesOptionsFetch is a GetOptions call shared by concurrent callers of
+// CachedOptions. Its result is set before done is closed.
+type kubernetesOptionsFetch struct {
+	done    chan struct{}
+	options *KubernetesOptions
+	err     error
 }
 
 // KubernetesClusterCreateRequest represents a request to create a Kubernetes cluster.
@@ -71,6 +148,11 @@ type KubernetesClusterCreateRequest struct {
 	Tags        []string `json:"tags,omitempty"`
 	VPCUUID     string   `json:"vpc_uuid,omitempty"`
 
+	// ClusterSubnet and ServiceSubnet are the CIDR blocks of the pod and
+	// service networks. They are chosen by the API if left empty.
+	ClusterSubnet string `json:"cluster_subnet,omitempty"`
+	ServiceSubnet string `json:"service_subnet,omitempty"`
+
 	// Create cluster with highly available control plane
 	HA bool `json:"ha"`
 
@@ -80,6 +162,8 @@ type KubernetesClusterCreateRequest struct {
 	AutoUpgrade          bool                            `json:"auto_upgrade"`
 	SurgeUpgrade         bool                            `json:"surge_upgrade"`
 	ControlPlaneFirewall *KubernetesControlPlaneFirewall `json:"control_plane_firewall,omitempty"`
+
+	ClusterAutoscalerConfiguration *KubernetesClusterAutoscalerConfiguration `json:"cluster_autoscaler_configuration,omitempty"`
 }
 
 // KubernetesClusterUpdateRequest represents a request to update a Kubernetes cluster.
@@ -91,7 +175,10 @@ type KubernetesClusterUpdateRequest struct {
 	SurgeUpgrade         bool                            `json:"surge_upgrade,omitempty"`
 	ControlPlaneFirewall *KubernetesControlPlaneFirewall `json:"control_plane_firewall,omitempty"`
 
-	// Convert cluster to run highly available control plane
+	ClusterAutoscalerConfiguration *KubernetesClusterAutoscalerConfiguration `json:"cluster_autoscaler_configuration,omitempty"`
+
+	// Convert cluster to run highly available control plane. Leave nil to keep
+	// the current setting.
 	HA *bool `json:"ha,omitempty"`
 }
 
@@ -122,6 +209,59 @@ func (t Taint) String() string {
 	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
 }
 
+// Possible taint effects.
+const (
+	TaintEffectNoSchedule       = "NoSchedule"
+	TaintEffectPreferNoSchedule = "PreferNoSchedule"
+	TaintEffectNoExecute        = "NoExecute"
+)
+
+// ParseTaint parses a taint in the form kubectl prints them, key=value:Effect
+// or key:Effect. It is the inverse of Taint.String.
+func ParseTaint(s string) (Taint, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return Taint{}, fmt.Errorf("invalid taint %q: missing effect", s)
+	}
+	keyValue, effect := s[:i], s[i+1:]
+	switch effect {
+	case TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute:
+	default:
+		return Taint{}, fmt.Errorf("invalid taint %q: unknown effect %q", s, effect)
+	}
+
+	key, value, _ := strings.Cut(keyValue, "=")
+	if key == "" {
+		return Taint{}, fmt.Errorf("invalid taint %q: missing key", s)
+	}
+	return Taint{Key: key, Value: value, Effect: effect}, nil
+}
+
+// NodePoolCreateRequestWithTaintStrings parses taints with ParseTaint and adds
+// them to the taints of base. If any taint is invalid, base is left unchanged
+// and an error naming all invalid taints is returned.
+func NodePoolCreateRequestWithTaintStrings(base *KubernetesNodePoolCreateRequest, taints []string) error {
+	if base == nil {
+		return NewArgError("base", "cannot be nil")
+	}
+
+	parsed := make([]Taint, 0, len(taints))
+	var errs []error
+	for _, s := range taints {
+		taint, err := ParseTaint(s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		parsed = append(parsed, taint)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	base.Taints = append(base.Taints, parsed...)
+	return nil
+}
+
 // KubernetesNodePoolCreateRequest represents a request to create a node pool for a
 // Kubernetes cluster.
 type KubernetesNodePoolCreateRequest struct {
@@ -181,10 +321,21 @@ type KubernetesRunClusterlintRequest struct {
 	ExcludeChecks []string `json:"exclude_checks"`
 }
 
+// KubernetesGetClusterlintRequest selects the clusterlint run to fetch the
+// results of.
 type KubernetesGetClusterlintRequest struct {
+	// RunId is the ID of the run. If empty, the results of the most recent
+	// run are returned.
 	RunId string `json:"run_id"`
 }
 
+// KubernetesGetClusterStatusMessagesRequest is a request to get the status
+// messages of a cluster.
+type KubernetesGetClusterStatusMessagesRequest struct {
+	// Since restricts the messages to those emitted after the given time.
+	Since *time.Time
+}
+
 // KubernetesCluster represents a Kubernetes cluster.
 type KubernetesCluster struct {
 	ID            string   `json:"id,omitempty"`
@@ -198,6 +349,11 @@ type KubernetesCluster struct {
 	Tags          []string `json:"tags,omitempty"`
 	VPCUUID       string   `json:"vpc_uuid,omitempty"`
 
+	// DNSDomain is the cluster-wide DNS domain of services, if the API
+	// reports it. The API does not send it today, so it is usually empty. Use
+	// ClusterDNSDomain to get it with the default applied.
+	DNSDomain string `json:"dns_domain,omitempty"`
+
 	// Cluster runs a highly available control plane
 	HA bool `json:"ha,omitempty"`
 
@@ -209,6 +365,8 @@ type KubernetesCluster struct {
 	RegistryEnabled      bool                            `json:"registry_enabled,omitempty"`
 	ControlPlaneFirewall *KubernetesControlPlaneFirewall `json:"control_plane_firewall,omitempty"`
 
+	ClusterAutoscalerConfiguration *KubernetesClusterAutoscalerConfiguration `json:"cluster_autoscaler_configuration,omitempty"`
+
 	Status    *KubernetesClusterStatus `json:"status,omitempty"`
 	CreatedAt time.Time                `json:"created_at,omitempty"`
 	UpdatedAt time.Time                `json:"updated_at,omitempty"`
@@ -219,6 +377,102 @@ func (kc KubernetesCluster) URN() string {
 	return ToURN("Kubernetes", kc.ID)
 }
 
+// String returns a one-line summary of the cluster for logs, listing its ID,
+// name, region, version, HA flag, number of node pools and state as key=value
+// pairs, e.g.
+// "id=abc name=prod region=nyc1 version=1.31.1-do.0 ha=true node_pools=2 state=running".
+func (kc KubernetesCluster) String() string {
+	var state KubernetesClusterStatusState
+	if kc.Status != nil {
+		state = kc.Status.State
+	}
+	return fmt.Sprintf("id=%s name=%s region=%s version=%s ha=%t node_pools=%d state=%s",
+		kc.ID, kc.Name, kc.RegionSlug, kc.VersionSlug, kc.HA, len(kc.NodePools), state)
+}
+
+// DefaultKubernetesClusterDNSDomain is the DNS domain of services in clusters
+// that do not report one.
+const DefaultKubernetesClusterDNSDomain = "cluster.local"
+
+// ClusterDNSDomain returns the cluster-wide DNS domain of services, such as
+// the cluster.local in my-svc.my-namespace.svc.cluster.local. It returns
+// DefaultKubernetesClusterDNSDomain if the API did not report a domain.
+func (kc *KubernetesCluster) ClusterDNSDomain() string {
+	if kc.DNSDomain == "" {
+		return DefaultKubernetesClusterDNSDomain
+	}
+	return kc.DNSDomain
+}
+
+// Possible values returned by UpgradeDowntimeRisk.
+const (
+	KubernetesUpgradeRiskNone = "none"
+	KubernetesUpgradeRiskLow  = "low"
+	KubernetesUpgradeRiskHigh = "high"
+)
+
+// UpgradeDowntimeRisk estimates the risk of downtime when upgrading the
+// cluster. The risk is high if the cluster has no node pools or a node pool
+// with at most one node, as its workloads go down while the node is replaced.
+// Otherwise, it is none if the control plane is highly available and surge
+// upgrades keep the node pools at capacity, and low if either is missing.
+func (kc *KubernetesCluster) UpgradeDowntimeRisk() string {
+	if len(kc.NodePools) == 0 {
+		return KubernetesUpgradeRiskHigh
+	}
+	for _, pool := range kc.NodePools {
+		if pool.Count <= 1 {
+			return KubernetesUpgradeRiskHigh
+		}
+	}
+	if kc.HA && kc.SurgeUpgrade {
+		return KubernetesUpgradeRiskNone
+	}
+	return KubernetesUpgradeRiskLow
+}
+
+// ToUpdateRequest returns an update request that keeps the cluster's current
+// settings, as a starting point for read-modify-write updates. Nested structs
+// are copied, so changing the request does not change the cluster. The tags
+// the API adds on its own are left out.
+func (kc *KubernetesCluster) ToUpdateRequest() *KubernetesClusterUpdateRequest {
+	return &KubernetesClusterUpdateRequest{
+		Name:                           kc.Name,
+		Tags:                           userTags(kc.Tags),
+		MaintenancePolicy:              kc.MaintenancePolicy.DeepCopy(),
+		AutoUpgrade:                    PtrTo(kc.AutoUpgrade),
+		SurgeUpgrade:                   kc.SurgeUpgrade,
+		ControlPlaneFirewall:           kc.ControlPlaneFirewall.DeepCopy(),
+		ClusterAutoscalerConfiguration: kc.ClusterAutoscalerConfiguration.DeepCopy(),
+		HA:                             PtrTo(kc.HA),
+	}
+}
+
+// ToCreateRequest returns a create request for a cluster of the same shape:
+// name, region, version, tags, HA, upgrade settings, node pools, maintenance
+// policy, control plane firewall and autoscaler configuration. The region is
+// meant to be overridden when cloning the cluster elsewhere. The VPC and the
+// subnets are left to the API to choose, as they depend on the region, and
+// the tags the API adds on its own are left out.
+func (kc *KubernetesCluster) ToCreateRequest() *KubernetesClusterCreateRequest {
+	req := &KubernetesClusterCreateRequest{
+		Name:                           kc.Name,
+		RegionSlug:                     kc.RegionSlug,
+		VersionSlug:                    kc.VersionSlug,
+		Tags:                           userTags(kc.Tags),
+		HA:                             kc.HA,
+		MaintenancePolicy:              kc.MaintenancePolicy.DeepCopy(),
+		AutoUpgrade:                    kc.AutoUpgrade,
+		SurgeUpgrade:                   kc.SurgeUpgrade,
+		ControlPlaneFirewall:           kc.ControlPlaneFirewall.DeepCopy(),
+		ClusterAutoscalerConfiguration: kc.ClusterAutoscalerConfiguration.DeepCopy(),
+	}
+	for _, pool := range kc.NodePools {
+		req.NodePools = append(req.NodePools, pool.ToCreateRequest())
+	}
+	return req
+}
+
 // KubernetesClusterUser represents a Kubernetes cluster user.
 type KubernetesClusterUser struct {
 	Username string   `json:"username,omitempty"`
@@ -249,6 +503,37 @@ type KubernetesControlPlaneFirewall struct {
 	AllowedAddresses []string `json:"allowed_addresses"`
 }
 
+// KubernetesClusterAutoscalerConfiguration represents the configuration of the
+// cluster autoscaler of a Kubernetes cluster.
+type KubernetesClusterAutoscalerConfiguration struct {
+	ScaleDownUtilizationThreshold *float64 `json:"scale_down_utilization_threshold"`
+	ScaleDownUnneededTime         *string  `json:"scale_down_unneeded_time"`
+}
+
+// Defaults the cluster autoscaler applies to unset configuration values.
+const (
+	DefaultKubernetesScaleDownUtilizationThreshold = 0.5
+	DefaultKubernetesScaleDownUnneededTime         = 10 * time.Minute
+)
+
+// EffectiveThreshold returns the configured scale down utilization threshold,
+// or the autoscaler's default if it is not set.
+func (c *KubernetesClusterAutoscalerConfiguration) EffectiveThreshold() float64 {
+	if c == nil || c.ScaleDownUtilizationThreshold == nil {
+		return DefaultKubernetesScaleDownUtilizationThreshold
+	}
+	return *c.ScaleDownUtilizationThreshold
+}
+
+// ScaleDownUnneededDuration parses the configured scale down unneeded time. It
+// returns the autoscaler's default if the value is not set.
+func (c *KubernetesClusterAutoscalerConfiguration) ScaleDownUnneededDuration() (time.Duration, error) {
+	if c == nil || c.ScaleDownUnneededTime == nil || *c.ScaleDownUnneededTime == "" {
+		return DefaultKubernetesScaleDownUnneededTime, nil
+	}
+	return time.ParseDuration(*c.ScaleDownUnneededTime)
+}
+
 // KubernetesMaintenancePolicyDay represents the possible days of a maintenance
 // window
 type KubernetesMaintenancePolicyDay int
@@ -398,6 +683,19 @@ type KubernetesClusterStatus struct {
 	Message string                       `json:"message,omitempty"`
 }
 
+// KubernetesClusterStatusMessage is a status message emitted for a cluster,
+// e.g. while it is being provisioned or upgraded.
+type KubernetesClusterStatusMessage struct {
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// KubernetesClusterEvent is an entry in the history of a cluster.
+type KubernetesClusterEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
 // KubernetesNodePool represents a node pool in a Kubernetes cluster.
 type KubernetesNodePool struct {
 	ID        string            `json:"id,omitempty"`
@@ -414,6 +712,35 @@ type KubernetesNodePool struct {
 	Nodes []*KubernetesNode `json:"nodes,omitempty"`
 }
 
+// String returns a one-line summary of the node pool for logs, e.g.
+// "name=workers size=s-2vcpu-4gb count=3 autoscale=1-5". Autoscale is "off"
+// for node pools that do not autoscale.
+func (p KubernetesNodePool) String() string {
+	autoscale := "off"
+	if p.AutoScale {
+		autoscale = fmt.Sprintf("%d-%d", p.MinNodes, p.MaxNodes)
+	}
+	return fmt.Sprintf("name=%s size=%s count=%d autoscale=%s", p.Name, p.Size, p.Count, autoscale)
+}
+
+// ToCreateRequest returns a create request for a node pool of the same shape:
+// name, size, count, tags, labels, taints and autoscaling range. Maps and
+// slices are copied, and the tags the API adds on its own are left out.
+func (p *KubernetesNodePool) ToCreateRequest() *KubernetesNodePoolCreateRequest {
+	cp := p.DeepCopy()
+	return &KubernetesNodePoolCreateRequest{
+		Name:      cp.Name,
+		Size:      cp.Size,
+		Count:     cp.Count,
+		Tags:      userTags(cp.Tags),
+		Labels:    cp.Labels,
+		Taints:    cp.Taints,
+		AutoScale: cp.AutoScale,
+		MinNodes:  cp.MinNodes,
+		MaxNodes:  cp.MaxNodes,
+	}
+}
+
 // KubernetesNode represents a Node in a node pool in a Kubernetes cluster.
 type KubernetesNode struct {
 	ID        string                `json:"id,omitempty"`
@@ -421,16 +748,154 @@ type KubernetesNode struct {
 	Status    *KubernetesNodeStatus `json:"status,omitempty"`
 	DropletID string                `json:"droplet_id,omitempty"`
 
+	// KubeletVersion is the version of the kubelet running on the node, if
+	// the API reports it. The API does not send it today, so it is usually
+	// empty.
+	KubeletVersion string `json:"kubelet_version,omitempty"`
+
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 }
 
+// KubernetesNodePoolStatus summarizes the health of a node pool from the
+// status of its nodes.
+type KubernetesNodePoolStatus struct {
+	// State is "error" if any node is in the error state, "running" if all
+	// nodes are running and "provisioning" otherwise.
+	State string
+	// NodeStates counts the nodes of the pool per state.
+	NodeStates map[string]int
+	// FailedNodes lists the nodes in the error state.
+	FailedNodes []*KubernetesNode
+	// Errors holds the distinct status messages of the failed nodes.
+	Errors []string
+}
+
+// KubernetesNodeWithPool is a node along with the node pool it belongs to.
+type KubernetesNodeWithPool struct {
+	*KubernetesNode
+
+	PoolID   string `json:"pool_id,omitempty"`
+	PoolName string `json:"pool_name,omitempty"`
+}
+
+// ErrKubernetesClusterNotFound is matched by errors.Is for errors returned when
+// a Kubernetes cluster does not exist.
+var ErrKubernetesClusterNotFound = errors.New("kubernetes cluster not found")
+
+// ErrKubernetesMultipleClustersFound is matched by errors.Is for errors
+// returned when a lookup by name matches more than one Kubernetes cluster.
+var ErrKubernetesMultipleClustersFound = errors.New("multiple kubernetes clusters found")
+
+// KubernetesClusterNotFoundError is returned by cluster operations when the
+// API responds with 404 Not Found. It matches ErrKubernetesClusterNotFound
+// with errors.Is, and the underlying *ErrorResponse with errors.As.
+type KubernetesClusterNotFoundError struct {
+	ClusterID string
+	Err       error
+}
+
+func (e *KubernetesClusterNotFoundError) Error() string {
+	return fmt.Sprintf("kubernetes cluster %s not found: %v", e.ClusterID, e.Err)
+}
+
+// Unwrap returns ErrKubernetesClusterNotFound and the underlying error.
+func (e *KubernetesClusterNotFoundError) Unwrap() []error {
+	return []error{ErrKubernetesClusterNotFound, e.Err}
+}
+
+// wrapKubernetesClusterNotFound wraps err in a KubernetesClusterNotFoundError
+// if it is a 404 response of the API. Other errors are returned unchanged.
+func wrapKubernetesClusterNotFound(clusterID string, err error) error {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return &KubernetesClusterNotFoundError{ClusterID: clusterID, Err: err}
+	}
+	return err
+}
+
+// ErrKubernetesNodeNotFound is returned when a node does not exist in a node
+// pool.
+var ErrKubernetesNodeNotFound = errors.New("kubernetes node not found")
+
+// ErrKubernetesClearUnsupported is returned when a request would remove all
+// labels or user tags of a cluster or node pool. Empty labels and tags are
+// omitted from update requests, which the API treats as leaving them
+// unchanged, so they cannot be cleared through an update.
+var ErrKubernetesClearUnsupported = errors.New("kubernetes API cannot clear all labels or tags")
+
 // KubernetesNodeStatus represents the status of a particular Node in a Kubernetes cluster.
 type KubernetesNodeStatus struct {
 	State   string `json:"state,omitempty"`
 	Message string `json:"message,omitempty"`
 }
 
+// KubernetesNodeErrorKind classifies the error reported for a node.
+type KubernetesNodeErrorKind string
+
+// Possible kinds of node errors.
+const (
+	// KubernetesNodeErrorTransient is an error that may resolve on its own,
+	// e.g. while the node is still being provisioned.
+	KubernetesNodeErrorTransient = KubernetesNodeErrorKind("transient")
+
+	// KubernetesNodeErrorPermanent is an error that requires user action,
+	// e.g. an exhausted quota.
+	KubernetesNodeErrorPermanent = KubernetesNodeErrorKind("permanent")
+)
+
+// permanentNodeErrorHints are message fragments indicating a node error that
+// will not resolve without user action.
+var permanentNodeErrorHints = []string{
+	"quota",
+	"limit exceeded",
+	"limit reached",
+	"insufficient",
+	"not available",
+	"unsupported",
+	"invalid",
+	"forbidden",
+	"unauthorized",
+}
+
+// ClassifyKubernetesNodeError classifies a node status message. Messages that
+// are not known to be permanent are considered transient.
+func ClassifyKubernetesNodeError(message string) KubernetesNodeErrorKind {
+	msg := strings.ToLower(message)
+	for _, hint := range permanentNodeErrorHints {
+		if strings.Contains(msg, hint) {
+			return KubernetesNodeErrorPermanent
+		}
+	}
+	return KubernetesNodeErrorTransient
+}
+
+// IsRecoverable reports whether the node can still become healthy without user
+// action. Nodes that are not in the error state are always recoverable; for
+// errored nodes, the status message is classified with
+// ClassifyKubernetesNodeError.
+func (n *KubernetesNode) IsRecoverable() bool {
+	if n.Status == nil || n.Status.State != "error" {
+		return true
+	}
+	return ClassifyKubernetesNodeError(n.Status.Message) != KubernetesNodeErrorPermanent
+}
+
+// ReadyLatency approximates how long the node took to become ready after it
+// was created. The API does not report when a node became ready, so the time
+// of the last update is used instead. This is accurate for fresh nodes but
+// overestimates the latency once the node was updated after becoming ready.
+// It returns false if the node is not running or its timestamps are missing.
+func (n *KubernetesNode) ReadyLatency() (time.Duration, bool) {
+	if n.Status == nil || n.Status.State != "running" {
+		return 0, false
+	}
+	if n.CreatedAt.IsZero() || n.UpdatedAt.Before(n.CreatedAt) {
+		return 0, false
+	}
+	return n.UpdatedAt.Sub(n.CreatedAt), true
+}
+
 // KubernetesOptions represents options available for creating Kubernetes clusters.
 type KubernetesOptions struct {
 	Versions []*KubernetesVersion  `json:"versions,omitempty"`
@@ -443,6 +908,18 @@ type KubernetesVersion struct {
 	Slug              string   `json:"slug,omitempty"`
 	KubernetesVersion string   `json:"kubernetes_version,omitempty"`
 	SupportedFeatures []string `json:"supported_features,omitempty"`
+
+	// SupportStart and SupportEnd bound the support window of the version's
+	// minor release, if the API reports it. The API does not send them today,
+	// so they are usually empty. They are kept as strings so that unexpected
+	// formats do not break decoding; use SupportWindow to parse them.
+	SupportStart string `json:"support_start,omitempty"`
+	SupportEnd   string `json:"support_end,omitempty"`
+
+	// Regions lists the slugs of the regions the version is available in, if
+	// the API reports it. The API does not send it today, so it is usually
+	// empty, which means the regions are unknown.
+	Regions []string `json:"regions,omitempty"`
 }
 
 // KubernetesNodeSize is a node sizes supported for Kubernetes clusters.
@@ -486,6 +963,68 @@ type KubernetesAssociatedResources struct {
 	LoadBalancers   []*AssociatedResource `json:"load_balancers"`
 }
 
+// SelectAll returns a selective delete request for all the associated
+// resources, for use with DeleteSelective.
+func (r *KubernetesAssociatedResources) SelectAll() *KubernetesClusterDeleteSelectiveRequest {
+	return r.selectResources(func(*AssociatedResource) bool { return true })
+}
+
+// SelectByName returns a selective delete request for the associated
+// resources whose name matches re, for use with DeleteSelective. A nil re
+// selects nothing.
+func (r *KubernetesAssociatedResources) SelectByName(re *regexp.Regexp) *KubernetesClusterDeleteSelectiveRequest {
+	return r.selectResources(func(res *AssociatedResource) bool {
+		return re != nil && re.MatchString(res.Name)
+	})
+}
+
+// selectResources returns a selective delete request for the associated
+// resources for which keep returns true.
+func (r *KubernetesAssociatedResources) selectResources(keep func(*AssociatedResource) bool) *KubernetesClusterDeleteSelectiveRequest {
+	ids := func(resources []*AssociatedResource) []string {
+		selected := []string{}
+		for _, res := range resources {
+			if res != nil && keep(res) {
+				selected = append(selected, res.ID)
+			}
+		}
+		return selected
+	}
+	return &KubernetesClusterDeleteSelectiveRequest{
+		Volumes:         ids(r.Volumes),
+		VolumeSnapshots: ids(r.VolumeSnapshots),
+		LoadBalancers:   ids(r.LoadBalancers),
+	}
+}
+
+// DangerousDeletePreflight summarizes everything a dangerous delete of a
+// cluster destroys, so that callers can ask for confirmation before calling
+// DeleteDangerous.
+type DangerousDeletePreflight struct {
+	ClusterID   string
+	ClusterName string
+	NodePools   []*KubernetesNodePool
+
+	Volumes         []*AssociatedResource
+	VolumeSnapshots []*AssociatedResource
+	LoadBalancers   []*AssociatedResource
+}
+
+// ConfirmsName reports whether name matches the name of the cluster, which is
+// the usual way to have a user confirm a dangerous delete.
+func (p *DangerousDeletePreflight) ConfirmsName(name string) bool {
+	return p.ClusterName != "" && name == p.ClusterName
+}
+
+// NodeCount returns the number of nodes across all node pools of the cluster.
+func (p *DangerousDeletePreflight) NodeCount() int {
+	var n int
+	for _, pool := range p.NodePools {
+		n += len(pool.Nodes)
+	}
+	return n
+}
+
 // AssociatedResource is the object to represent a Kubernetes cluster associated resource's ID and Name.
 type AssociatedResource struct {
 	ID   string `json:"id"`
@@ -519,6 +1058,10 @@ type kubernetesUpgradesRoot struct {
 	AvailableUpgradeVersions []*KubernetesVersion `json:"available_upgrade_versions,omitempty"`
 }
 
+type kubernetesClusterStatusMessagesRoot struct {
+	Messages []*KubernetesClusterStatusMessage `json:"messages,omitempty"`
+}
+
 // Get retrieves the details of a Kubernetes cluster.
 func (svc *KubernetesServiceOp) Get(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error) {
 	path := fmt.Sprintf("%s/%s", kubernetesClustersPath, clusterID)
@@ -527,13 +1070,28 @@ func (svc *KubernetesServiceOp) Get(ctx context.Context, clusterID string) (*Kub
 		return nil, nil, err
 	}
 	root := new(kubernetesClusterRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.doRead(ctx, req, root)
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return root.Cluster, resp, nil
 }
 
+// GetClusterByName pages through all clusters and returns the one with the
+// given name. It returns an error matching ErrKubernetesClusterNotFound if
+// there is none, and ErrKubernetesMultipleClustersFound if several clusters
+// share the name.
+func (svc *KubernetesServiceOp) GetClusterByName(ctx context.Context, name string) (*KubernetesCluster, *Response, error) {
+	cluster, resp, err := svc.findClusterByName(ctx, name)
+	if err != nil {
+		return nil, resp, err
+	}
+	if cluster == nil {
+		return nil, resp, fmt.Errorf("%w with name %q", ErrKubernetesClusterNotFound, name)
+	}
+	return cluster, resp, nil
+}
+
 // GetUser retrieves the details of a Kubernetes cluster user.
 func (svc *KubernetesServiceOp) GetUser(ctx context.Context, clusterID string) (*KubernetesClusterUser, *Response, error) {
 	path := fmt.Sprintf("%s/%s/user", kubernetesClustersPath, clusterID)
@@ -544,7 +1102,7 @@ func (svc *KubernetesServiceOp) GetUser(ctx context.Context, clusterID string) (
 	root := new(kubernetesClusterUserRoot)
 	resp, err := svc.client.Do(ctx, req, root)
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return root.User, resp, nil
 }
@@ -560,18 +1118,43 @@ func (svc *KubernetesServiceOp) GetUpgrades(ctx context.Context, clusterID strin
 	root := new(kubernetesUpgradesRoot)
 	resp, err := svc.client.Do(ctx, req, root)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return root.AvailableUpgradeVersions, resp, nil
 }
 
-// Create creates a Kubernetes cluster.
+// Create creates a Kubernetes cluster. The request is checked with Validate
+// before it is sent.
 func (svc *KubernetesServiceOp) Create(ctx context.Context, create *KubernetesClusterCreateRequest) (*KubernetesCluster, *Response, error) {
+	return svc.CreateWithOptions(ctx, create, nil)
+}
+
+// KubernetesClusterCreateOptions configures how a cluster create request is
+// sent.
+type KubernetesClusterCreateOptions struct {
+	// IdempotencyKey is sent in the Idempotency-Key header. If a create is
+	// retried with the same key, e.g. after a network error, the API returns
+	// the cluster created by the first request instead of creating a second
+	// one. Use a new unique value, such as a UUID, for each cluster.
+	IdempotencyKey string
+}
+
+// CreateWithOptions creates a Kubernetes cluster like Create, sending the
+// request according to opts. A nil opts is the same as calling Create.
+func (svc *KubernetesServiceOp) CreateWithOptions(ctx context.Context, create *KubernetesClusterCreateRequest, opts *KubernetesClusterCreateOptions) (*KubernetesCluster, *Response, error) {
+	if create != nil {
+		if err := create.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 	path := kubernetesClustersPath
 	req, err := svc.client.NewRequest(ctx, http.MethodPost, path, create)
 	if err != nil {
 		return nil, nil, err
 	}
+	if opts != nil && opts.IdempotencyKey != "" {
+		req.Header.Set(headerIdempotencyKey, opts.IdempotencyKey)
+	}
 	root := new(kubernetesClusterRoot)
 	resp, err := svc.client.Do(ctx, req, root)
 	if err != nil {
@@ -590,7 +1173,7 @@ func (svc *KubernetesServiceOp) Delete(ctx context.Context, clusterID string) (*
 	}
 	resp, err := svc.client.Do(ctx, req, nil)
 	if err != nil {
-		return resp, err
+		return resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return resp, nil
 }
@@ -606,7 +1189,7 @@ func (svc *KubernetesServiceOp) DeleteSelective(ctx context.Context, clusterID s
 	}
 	resp, err := svc.client.Do(ctx, req, nil)
 	if err != nil {
-		return resp, err
+		return resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return resp, nil
 }
@@ -621,7 +1204,7 @@ func (svc *KubernetesServiceOp) DeleteDangerous(ctx context.Context, clusterID s
 	}
 	resp, err := svc.client.Do(ctx, req, nil)
 	if err != nil {
-		return resp, err
+		return resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return resp, nil
 }
@@ -638,11 +1221,122 @@ func (svc *KubernetesServiceOp) ListAssociatedResourcesForDeletion(ctx context.C
 	root := new(KubernetesAssociatedResources)
 	resp, err := svc.client.Do(ctx, req, root)
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return root, resp, nil
 }
 
+// PreflightDangerousDelete fetches the cluster and the resources associated
+// with it and returns everything a call to DeleteDangerous would destroy. The
+// API has no deletion protection for Kubernetes clusters, so nothing in the
+// result prevents the delete; it is meant to back a confirmation step.
+func (svc *KubernetesServiceOp) PreflightDangerousDelete(ctx context.Context, clusterID string) (*DangerousDeletePreflight, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+	if cluster.Name == "" {
+		return nil, resp, fmt.Errorf("cluster %s has no name to confirm the delete with", clusterID)
+	}
+
+	resources, resp, err := svc.ListAssociatedResourcesForDeletion(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &DangerousDeletePreflight{
+		ClusterID:       cluster.ID,
+		ClusterName:     cluster.Name,
+		NodePools:       cluster.NodePools,
+		Volumes:         resources.Volumes,
+		VolumeSnapshots: resources.VolumeSnapshots,
+		LoadBalancers:   resources.LoadBalancers,
+	}, resp, nil
+}
+
+// GetClusterStatusMessages returns the status messages of a Kubernetes cluster,
+// optionally restricted to those emitted after req.Since.
+func (svc *KubernetesServiceOp) GetClusterStatusMessages(ctx context.Context, clusterID string, req *KubernetesGetClusterStatusMessagesRequest) ([]*KubernetesClusterStatusMessage, *Response, error) {
+	path := fmt.Sprintf("%s/%s/status_messages", kubernetesClustersPath, clusterID)
+	if req != nil && req.Since != nil {
+		v := make(url.Values)
+		v.Set("since", req.Since.Format(time.RFC3339))
+		path = path + "?" + v.Encode()
+	}
+
+	request, err := svc.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := new(kubernetesClusterStatusMessagesRoot)
+	resp, err := svc.client.Do(ctx, request, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	return root.Messages, resp, nil
+}
+
+// GetRecentClusterStatusMessages returns the n most recent status messages of a
+// Kubernetes cluster, oldest first. Fewer messages are returned if the cluster
+// has less than n.
+func (svc *KubernetesServiceOp) GetRecentClusterStatusMessages(ctx context.Context, clusterID string, n int) ([]*KubernetesClusterStatusMessage, *Response, error) {
+	if n <= 0 {
+		return nil, nil, NewArgError("n", "cannot be less than 1")
+	}
+
+	messages, resp, err := svc.GetClusterStatusMessages(ctx, clusterID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	if len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+	return messages, resp, nil
+}
+
+// GetClusterHistory returns the history of a Kubernetes cluster, oldest event
+// first. The API has no dedicated changelog endpoint, so the history is built
+// from the cluster's status messages and only reaches back as far as those are
+// retained. The page and page size in opts are applied to the sorted events.
+func (svc *KubernetesServiceOp) GetClusterHistory(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesClusterEvent, *Response, error) {
+	messages, resp, err := svc.GetClusterStatusMessages(ctx, clusterID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	events := make([]*KubernetesClusterEvent, 0, len(messages))
+	for _, m := range messages {
+		events = append(events, &KubernetesClusterEvent{
+			Timestamp: m.Timestamp,
+			Message:   m.Message,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	if opts != nil && opts.PerPage > 0 {
+		page := opts.Page
+		if page < 1 {
+			page = 1
+		}
+		start := (page - 1) * opts.PerPage
+		if start > len(events) {
+			start = len(events)
+		}
+		end := start + opts.PerPage
+		if end > len(events) {
+			end = len(events)
+		}
+		events = events[start:end]
+	}
+
+	return events, resp, nil
+}
+
 // List returns a list of the Kubernetes clusters visible with the caller's API token.
 func (svc *KubernetesServiceOp) List(ctx context.Context, opts *ListOptions) ([]*KubernetesCluster, *Response, error) {
 	path := kubernetesClustersPath
@@ -655,7 +1349,7 @@ func (svc *KubernetesServiceOp) List(ctx context.Context, opts *ListOptions) ([]
 		return nil, nil, err
 	}
 	root := new(kubernetesClustersRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.doRead(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -670,49 +1364,173 @@ func (svc *KubernetesServiceOp) List(ctx context.Context, opts *ListOptions) ([]
 	return root.Clusters, resp, nil
 }
 
+// ListClustersAll pages through List and returns all clusters, starting at the
+// page given in opts. Pages hold DefaultKubernetesListAllPerPage clusters
+// unless opts sets PerPage. The returned response is the one of the last page.
+func (svc *KubernetesServiceOp) ListClustersAll(ctx context.Context, opts *ListOptions) ([]*KubernetesCluster, *Response, error) {
+	o := listAllOptions(opts)
+
+	var all []*KubernetesCluster
+	for {
+		clusters, resp, err := svc.List(ctx, &o)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, clusters...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return all, resp, nil
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, resp, err
+		}
+		o.Page = page + 1
+	}
+}
+
+// DefaultKubernetesListAllPerPage is the page size ListClustersAll and
+// ListNodePoolsAll use when the caller does not set one, to reduce the number
+// of requests needed to list everything.
+const DefaultKubernetesListAllPerPage = 200
+
+// listAllOptions returns a copy of opts, with PerPage defaulting to
+// DefaultKubernetesListAllPerPage.
+func listAllOptions(opts *ListOptions) ListOptions {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.PerPage <= 0 {
+		o.PerPage = DefaultKubernetesListAllPerPage
+	}
+	return o
+}
+
+// ListUnhealthyClusters returns all clusters that are in the degraded or error
+// state.
+func (svc *KubernetesServiceOp) ListUnhealthyClusters(ctx context.Context) ([]*KubernetesCluster, *Response, error) {
+	clusters, resp, err := svc.ListClustersAll(ctx, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var unhealthy []*KubernetesCluster
+	for _, c := range clusters {
+		if c.Status == nil {
+			continue
+		}
+		switch c.Status.State {
+		case KubernetesClusterStatusDegraded, KubernetesClusterStatusError:
+			unhealthy = append(unhealthy, c)
+		}
+	}
+	return unhealthy, resp, nil
+}
+
+// ListClustersByTag pages through all clusters and returns those tagged with
+// tag. As the API has no tag filter for clusters, the filtering is done on the
+// client. Tags are compared exactly, including case, as the API does.
+func (svc *KubernetesServiceOp) ListClustersByTag(ctx context.Context, tag string, opts *ListOptions) ([]*KubernetesCluster, *Response, error) {
+	if tag == "" {
+		return nil, nil, NewArgError("tag", "cannot be empty")
+	}
+	clusters, resp, err := svc.ListClustersAll(ctx, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var tagged []*KubernetesCluster
+	for _, c := range clusters {
+		for _, t := range c.Tags {
+			if t == tag {
+				tagged = append(tagged, c)
+				break
+			}
+		}
+	}
+	return tagged, resp, nil
+}
+
+// KubernetesKubeConfigOptions configures how the Kubernetes config file of a
+// cluster is generated.
+type KubernetesKubeConfigOptions struct {
+	// ExpirySeconds sets the lifetime of the credentials embedded in the
+	// config file. If nil, the API default is used.
+	ExpirySeconds *int64
+
+	// ContextName renames the current context of the config file, and the
+	// cluster and user it refers to, from the generated do-<region>-<name>.
+	// This avoids collisions when merging the config files of clusters of
+	// several accounts. If empty, the names are kept.
+	ContextName string
+}
+
 // KubernetesClusterConfig is the content of a Kubernetes config file, which can be
 // used to interact with your Kubernetes cluster using `kubectl`.
 // See: https://kubernetes.io/docs/tasks/tools/install-kubectl/
 type KubernetesClusterConfig struct {
 	KubeconfigYAML []byte
+
+	// ExpiresAt is when the embedded credentials expire, as reported by
+	// TokenExpiry. DOKS tokens are usually opaque, so if TokenExpiry cannot
+	// tell and an expiry was requested with ExpirySeconds, it is the time of
+	// the request plus ExpirySeconds. It is zero if the expiry cannot be
+	// determined, e.g. because the credentials are obtained through an exec
+	// plugin.
+	ExpiresAt time.Time
 }
 
 // GetKubeConfig returns a Kubernetes config file for the specified cluster.
 func (svc *KubernetesServiceOp) GetKubeConfig(ctx context.Context, clusterID string) (*KubernetesClusterConfig, *Response, error) {
-	path := fmt.Sprintf("%s/%s/kubeconfig", kubernetesClustersPath, clusterID)
-	req, err := svc.client.NewRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-	configBytes := bytes.NewBuffer(nil)
-	resp, err := svc.client.Do(ctx, req, configBytes)
-	if err != nil {
-		return nil, resp, err
-	}
-	res := &KubernetesClusterConfig{
-		KubeconfigYAML: configBytes.Bytes(),
-	}
-	return res, resp, nil
+	return svc.GetKubeConfigWithOptions(ctx, clusterID, nil)
 }
 
 // GetKubeConfigWithExpiry returns a Kubernetes config file for the specified cluster with expiry_seconds.
 func (svc *KubernetesServiceOp) GetKubeConfigWithExpiry(ctx context.Context, clusterID string, expirySeconds int64) (*KubernetesClusterConfig, *Response, error) {
+	return svc.GetKubeConfigWithOptions(ctx, clusterID, &KubernetesKubeConfigOptions{ExpirySeconds: &expirySeconds})
+}
+
+// GetKubeConfigWithOptions returns a Kubernetes config file for the specified
+// cluster, generated according to opts. A nil opts is the same as calling
+// GetKubeConfig.
+func (svc *KubernetesServiceOp) GetKubeConfigWithOptions(ctx context.Context, clusterID string, opts *KubernetesKubeConfigOptions) (*KubernetesClusterConfig, *Response, error) {
 	path := fmt.Sprintf("%s/%s/kubeconfig", kubernetesClustersPath, clusterID)
 	req, err := svc.client.NewRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	q := req.URL.Query()
-	q.Add("expiry_seconds", fmt.Sprintf("%d", expirySeconds))
-	req.URL.RawQuery = q.Encode()
+	if opts != nil && opts.ExpirySeconds != nil {
+		q := req.URL.Query()
+		q.Add("expiry_seconds", fmt.Sprintf("%d", *opts.ExpirySeconds))
+		req.URL.RawQuery = q.Encode()
+	}
+	requestedAt := time.Now()
 	configBytes := bytes.NewBuffer(nil)
 	resp, err := svc.client.Do(ctx, req, configBytes)
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	res := &KubernetesClusterConfig{
 		KubeconfigYAML: configBytes.Bytes(),
 	}
+	if opts != nil && opts.ContextName != "" {
+		kc, err := parseKubeconfig(res.KubeconfigYAML)
+		if err != nil {
+			return nil, resp, fmt.Errorf("parsing kubeconfig: %w", err)
+		}
+		if err := kc.renameCurrentContext(opts.ContextName); err != nil {
+			return nil, resp, err
+		}
+		if res.KubeconfigYAML, err = kc.marshal(); err != nil {
+			return nil, resp, err
+		}
+	}
+	if expiresAt, ok := res.TokenExpiry(); ok {
+		res.ExpiresAt = expiresAt
+	} else if opts != nil && opts.ExpirySeconds != nil && *opts.ExpirySeconds > 0 {
+		res.ExpiresAt = requestedAt.Add(time.Duration(*opts.ExpirySeconds) * time.Second)
+	}
 	return res, resp, nil
 }
 
@@ -731,13 +1549,23 @@ func (svc *KubernetesServiceOp) GetCredentials(ctx context.Context, clusterID st
 	credentials := new(KubernetesClusterCredentials)
 	resp, err := svc.client.Do(ctx, req, credentials)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return credentials, resp, nil
 }
 
 // Update updates a Kubernetes cluster's properties.
 func (svc *KubernetesServiceOp) Update(ctx context.Context, clusterID string, update *KubernetesClusterUpdateRequest) (*KubernetesCluster, *Response, error) {
+	if update != nil && update.MaintenancePolicy != nil {
+		if err := update.MaintenancePolicy.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if update != nil && update.ControlPlaneFirewall.isEnabled() {
+		if err := update.ControlPlaneFirewall.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 	path := fmt.Sprintf("%s/%s", kubernetesClustersPath, clusterID)
 	req, err := svc.client.NewRequest(ctx, http.MethodPut, path, update)
 	if err != nil {
@@ -746,11 +1574,68 @@ func (svc *KubernetesServiceOp) Update(ctx context.Context, clusterID string, up
 	root := new(kubernetesClusterRoot)
 	resp, err := svc.client.Do(ctx, req, root)
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, wrapKubernetesClusterNotFound(clusterID, err)
 	}
 	return root.Cluster, resp, nil
 }
 
+// ErrKubernetesHADowngradeUnsupported is returned by DisableHA, as a highly
+// available control plane cannot be turned back into a single one.
+var ErrKubernetesHADowngradeUnsupported = errors.New("kubernetes cluster HA cannot be disabled once enabled")
+
+// EnableHA converts the cluster's control plane to a highly available one.
+// This cannot be undone. Like PauseAutoUpgrade, the cluster is fetched first
+// and its other settings are sent back unchanged. Nothing is updated if the
+// control plane is already highly available.
+func (svc *KubernetesServiceOp) EnableHA(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+	if cluster.HA {
+		return cluster, resp, nil
+	}
+	update := cluster.ToUpdateRequest()
+	update.HA = PtrTo(true)
+	return svc.Update(ctx, clusterID, update)
+}
+
+// DisableHA always returns ErrKubernetesHADowngradeUnsupported without making
+// a request, as DigitalOcean does not support turning off a highly available
+// control plane. To leave HA, create a new cluster without it.
+func (svc *KubernetesServiceOp) DisableHA(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error) {
+	return nil, nil, fmt.Errorf("cluster %s: %w", clusterID, ErrKubernetesHADowngradeUnsupported)
+}
+
+// PauseAutoUpgrade turns off automatic upgrades of the cluster, e.g. during a
+// change freeze. The cluster is fetched first and its other settings are sent
+// back unchanged, so that they are not cleared by the update. Nothing is
+// updated if automatic upgrades are already off.
+func (svc *KubernetesServiceOp) PauseAutoUpgrade(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error) {
+	return svc.setAutoUpgrade(ctx, clusterID, false)
+}
+
+// ResumeAutoUpgrade turns automatic upgrades of the cluster back on. Like
+// PauseAutoUpgrade, it keeps the cluster's other settings.
+func (svc *KubernetesServiceOp) ResumeAutoUpgrade(ctx context.Context, clusterID string) (*KubernetesCluster, *Response, error) {
+	return svc.setAutoUpgrade(ctx, clusterID, true)
+}
+
+// setAutoUpgrade sets AutoUpgrade on the cluster with a read-modify-write
+// update built by ToUpdateRequest.
+func (svc *KubernetesServiceOp) setAutoUpgrade(ctx context.Context, clusterID string, enabled bool) (*KubernetesCluster, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+	if cluster.AutoUpgrade == enabled {
+		return cluster, resp, nil
+	}
+	update := cluster.ToUpdateRequest()
+	update.AutoUpgrade = PtrTo(enabled)
+	return svc.Update(ctx, clusterID, update)
+}
+
 // Upgrade upgrades a Kubernetes cluster to a new version. Valid upgrade
 // versions for a given cluster can be retrieved with `GetUpgrades`.
 func (svc *KubernetesServiceOp) Upgrade(ctx context.Context, clusterID string, upgrade *KubernetesClusterUpgradeRequest) (*Response, error) {
@@ -759,11 +1644,18 @@ func (svc *KubernetesServiceOp) Upgrade(ctx context.Context, clusterID string, u
 	if err != nil {
 		return nil, err
 	}
-	return svc.client.Do(ctx, req, nil)
+	resp, err := svc.client.Do(ctx, req, nil)
+	return resp, wrapKubernetesClusterNotFound(clusterID, err)
 }
 
-// CreateNodePool creates a new node pool in an existing Kubernetes cluster.
+// CreateNodePool creates a new node pool in an existing Kubernetes cluster. The
+// request is checked with Validate before it is sent.
 func (svc *KubernetesServiceOp) CreateNodePool(ctx context.Context, clusterID string, create *KubernetesNodePoolCreateRequest) (*KubernetesNodePool, *Response, error) {
+	if create != nil {
+		if err := create.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 	path := fmt.Sprintf("%s/%s/node_pools", kubernetesClustersPath, clusterID)
 	req, err := svc.client.NewRequest(ctx, http.MethodPost, path, create)
 	if err != nil {
@@ -785,13 +1677,69 @@ func (svc *KubernetesServiceOp) GetNodePool(ctx context.Context, clusterID, pool
 		return nil, nil, err
 	}
 	root := new(kubernetesNodePoolRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.doRead(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
 	return root.NodePool, resp, nil
 }
 
+// GetNode retrieves a node of a node pool. As there is no endpoint for single
+// nodes, the node pool is fetched and searched for the node. If the node pool
+// has no node with the given ID, an error wrapping ErrKubernetesNodeNotFound
+// is returned.
+func (svc *KubernetesServiceOp) GetNode(ctx context.Context, clusterID, poolID, nodeID string) (*KubernetesNode, *Response, error) {
+	pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, node := range pool.Nodes {
+		if node.ID == nodeID {
+			return node, resp, nil
+		}
+	}
+	return nil, resp, fmt.Errorf("node %s in node pool %s of cluster %s: %w", nodeID, poolID, clusterID, ErrKubernetesNodeNotFound)
+}
+
+// GetNodePoolStatus retrieves a node pool and aggregates the status of its
+// nodes, giving a single place to check whether the pool is healthy or why its
+// creation failed.
+func (svc *KubernetesServiceOp) GetNodePoolStatus(ctx context.Context, clusterID, poolID string) (*KubernetesNodePoolStatus, *Response, error) {
+	pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+	if err != nil {
+		return nil, resp, err
+	}
+	return nodePoolStatus(pool), resp, nil
+}
+
+func nodePoolStatus(pool *KubernetesNodePool) *KubernetesNodePoolStatus {
+	status := &KubernetesNodePoolStatus{
+		State:      "running",
+		NodeStates: make(map[string]int),
+	}
+	seen := make(map[string]bool)
+	for _, node := range pool.Nodes {
+		var state, message string
+		if node.Status != nil {
+			state, message = node.Status.State, node.Status.Message
+		}
+		status.NodeStates[state]++
+
+		switch {
+		case state == "error":
+			status.State = "error"
+			status.FailedNodes = append(status.FailedNodes, node)
+			if message != "" && !seen[message] {
+				seen[message] = true
+				status.Errors = append(status.Errors, message)
+			}
+		case state != "running" && status.State != "error":
+			status.State = "provisioning"
+		}
+	}
+	return status
+}
+
 // ListNodePools lists all the node pools found in a Kubernetes cluster.
 func (svc *KubernetesServiceOp) ListNodePools(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesNodePool, *Response, error) {
 	path := fmt.Sprintf("%s/%s/node_pools", kubernetesClustersPath, clusterID)
@@ -804,13 +1752,141 @@ func (svc *KubernetesServiceOp) ListNodePools(ctx context.Context, clusterID str
 		return nil, nil, err
 	}
 	root := new(kubernetesNodePoolsRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.doRead(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
+	if l := root.Links; l != nil {
+		resp.Links = l
+	}
 	return root.NodePools, resp, nil
 }
 
+// ListNodePoolsAll pages through ListNodePools and returns all node pools of a
+// Kubernetes cluster, starting at the page given in opts. Pages hold
+// DefaultKubernetesListAllPerPage node pools unless opts sets PerPage. The
+// returned response is the one of the last page.
+func (svc *KubernetesServiceOp) ListNodePoolsAll(ctx context.Context, clusterID string, opts *ListOptions) ([]*KubernetesNodePool, *Response, error) {
+	o := listAllOptions(opts)
+
+	var all []*KubernetesNodePool
+	for {
+		pools, resp, err := svc.ListNodePools(ctx, clusterID, &o)
+		if err != nil {
+			return nil, resp, err
+		}
+		all = append(all, pools...)
+
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			return all, resp, nil
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, resp, err
+		}
+		o.Page = page + 1
+	}
+}
+
+// ListAllNodes returns the nodes of all node pools of a Kubernetes cluster,
+// along with the pool they belong to. The nodes are ordered by pool name and
+// then by node name.
+func (svc *KubernetesServiceOp) ListAllNodes(ctx context.Context, clusterID string) ([]*KubernetesNodeWithPool, error) {
+	pools, _, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*KubernetesNodeWithPool
+	for _, pool := range pools {
+		for _, node := range pool.Nodes {
+			nodes = append(nodes, &KubernetesNodeWithPool{
+				KubernetesNode: node,
+				PoolID:         pool.ID,
+				PoolName:       pool.Name,
+			})
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].PoolName != nodes[j].PoolName {
+			return nodes[i].PoolName < nodes[j].PoolName
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes, nil
+}
+
+// ListNodePoolsForClusters lists the node pools of several Kubernetes clusters,
+// keyed by cluster ID. Up to concurrency clusters are queried at once; values
+// below 1 query one cluster at a time. Requests go through the client, so a
+// rate limit configured with SetStaticRateLimit is honored, and no new
+// clusters are queried while the API rate limit is exhausted. Failures for
+// individual clusters are joined into the returned error, while the pools of
+// the other clusters are still returned.
+func (svc *KubernetesServiceOp) ListNodePoolsForClusters(ctx context.Context, clusterIDs []string, concurrency int) (map[string][]*KubernetesNodePool, error) {
+	var (
+		mu    sync.Mutex
+		pools = make(map[string][]*KubernetesNodePool, len(clusterIDs))
+	)
+	errs := runFleet(ctx, defaultFleetClock, clusterIDs, concurrency, func(ctx context.Context, clusterID string) (*Response, error) {
+		clusterPools, resp, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+		if err != nil {
+			return resp, fmt.Errorf("listing node pools of cluster %s: %w", clusterID, err)
+		}
+		mu.Lock()
+		pools[clusterID] = clusterPools
+		mu.Unlock()
+		return resp, nil
+	})
+
+	return pools, errors.Join(errs...)
+}
+
+// ListUnderMinNodePools returns the autoscaling node pools of a cluster that
+// have fewer running nodes than their MinNodes, e.g. because nodes failed and
+// could not be replaced.
+func (svc *KubernetesServiceOp) ListUnderMinNodePools(ctx context.Context, clusterID string) ([]*KubernetesNodePool, *Response, error) {
+	pools, resp, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var underMin []*KubernetesNodePool
+	for _, pool := range pools {
+		if !pool.AutoScale {
+			continue
+		}
+		running := 0
+		for _, node := range pool.Nodes {
+			if node.Status != nil && node.Status.State == "running" {
+				running++
+			}
+		}
+		if running < pool.MinNodes {
+			underMin = append(underMin, pool)
+		}
+	}
+	return underMin, resp, nil
+}
+
+// CurrentSurgeNodes returns the number of nodes the cluster runs beyond the
+// count of its node pools, which are the extra nodes created during a surge
+// upgrade.
+func (svc *KubernetesServiceOp) CurrentSurgeNodes(ctx context.Context, clusterID string) (int, *Response, error) {
+	pools, resp, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return 0, resp, err
+	}
+
+	surge := 0
+	for _, pool := range pools {
+		if extra := len(pool.Nodes) - pool.Count; extra > 0 {
+			surge += extra
+		}
+	}
+	return surge, resp, nil
+}
+
 // UpdateNodePool updates the details of an existing node pool.
 func (svc *KubernetesServiceOp) UpdateNodePool(ctx context.Context, clusterID, poolID string, update *KubernetesNodePoolUpdateRequest) (*KubernetesNodePool, *Response, error) {
 	path := fmt.Sprintf("%s/%s/node_pools/%s", kubernetesClustersPath, clusterID, poolID)
@@ -826,6 +1902,271 @@ func (svc *KubernetesServiceOp) UpdateNodePool(ctx context.Context, clusterID, p
 	return root.NodePool, resp, nil
 }
 
+// UpdateNodePoolWithRetry performs a read-modify-write update of a node pool.
+// It fetches the node pool, passes it to mutate and sends the returned update.
+// If the update fails with 409 Conflict, because the node pool was changed
+// concurrently, the node pool is fetched again and mutate re-applied, up to
+// maxRetries times. If mutate returns nil, nothing is updated and the fetched
+// node pool is returned.
+func (svc *KubernetesServiceOp) UpdateNodePoolWithRetry(ctx context.Context, clusterID, poolID string, mutate func(*KubernetesNodePool) *KubernetesNodePoolUpdateRequest, maxRetries int) (*KubernetesNodePool, *Response, error) {
+	if mutate == nil {
+		return nil, nil, NewArgError("mutate", "cannot be nil")
+	}
+	if maxRetries < 0 {
+		return nil, nil, NewArgError("maxRetries", "cannot be less than 0")
+	}
+
+	for attempt := 0; ; attempt++ {
+		pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+		if err != nil {
+			return nil, resp, err
+		}
+		update := mutate(pool)
+		if update == nil {
+			return pool, resp, nil
+		}
+
+		updated, resp, err := svc.UpdateNodePool(ctx, clusterID, poolID, update)
+		if err == nil {
+			return updated, resp, nil
+		}
+		if attempt >= maxRetries || resp == nil || resp.StatusCode != http.StatusConflict {
+			return nil, resp, err
+		}
+	}
+}
+
+// ScaleNodePool sets the number of nodes in an existing node pool. It is a
+// shorthand for UpdateNodePool with only the count set.
+func (svc *KubernetesServiceOp) ScaleNodePool(ctx context.Context, clusterID, poolID string, count int) (*KubernetesNodePool, *Response, error) {
+	if count < 0 {
+		return nil, nil, NewArgError("count", "cannot be less than 0")
+	}
+	update := &KubernetesNodePoolUpdateRequest{
+		Count: PtrTo(count),
+	}
+	return svc.UpdateNodePool(ctx, clusterID, poolID, update)
+}
+
+// kubernetesNodePoolPatchRetries is the number of times the helpers that
+// change part of a node pool, such as AddTaint and MergeLabels, retry after a
+// conflicting concurrent update of the node pool.
+const kubernetesNodePoolPatchRetries = 3
+
+// AddTaint adds a taint to an existing node pool, replacing any taint with the
+// same key. The node pool is fetched first so that its other taints are kept.
+// Nothing is updated if the node pool already has the taint.
+func (svc *KubernetesServiceOp) AddTaint(ctx context.Context, clusterID, poolID string, t Taint) (*KubernetesNodePool, *Response, error) {
+	if t.Key == "" {
+		return nil, nil, NewArgError("t.Key", "cannot be empty")
+	}
+	return svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		taints := make([]Taint, 0, len(pool.Taints)+1)
+		replaced := false
+		for _, existing := range pool.Taints {
+			if existing.Key != t.Key {
+				taints = append(taints, existing)
+				continue
+			}
+			if existing == t {
+				return nil
+			}
+			if !replaced {
+				taints = append(taints, t)
+				replaced = true
+			}
+		}
+		if !replaced {
+			taints = append(taints, t)
+		}
+		return &KubernetesNodePoolUpdateRequest{Taints: &taints}
+	}, kubernetesNodePoolPatchRetries)
+}
+
+// RemoveTaint removes the taint with the given key from an existing node pool.
+// The node pool is fetched first so that its other taints are kept. Nothing is
+// updated if the node pool has no taint with the key.
+func (svc *KubernetesServiceOp) RemoveTaint(ctx context.Context, clusterID, poolID string, key string) (*KubernetesNodePool, *Response, error) {
+	if key == "" {
+		return nil, nil, NewArgError("key", "cannot be empty")
+	}
+	return svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		taints := make([]Taint, 0, len(pool.Taints))
+		for _, existing := range pool.Taints {
+			if existing.Key != key {
+				taints = append(taints, existing)
+			}
+		}
+		if len(taints) == len(pool.Taints) {
+			return nil
+		}
+		return &KubernetesNodePoolUpdateRequest{Taints: &taints}
+	}, kubernetesNodePoolPatchRetries)
+}
+
+// MergeLabels adds labels to an existing node pool, overwriting the values of
+// labels that already exist. Unlike UpdateNodePool, which replaces all labels,
+// the node pool's other labels are kept. Nothing is updated if the node pool
+// already has all the labels.
+func (svc *KubernetesServiceOp) MergeLabels(ctx context.Context, clusterID, poolID string, labels map[string]string) (*KubernetesNodePool, *Response, error) {
+	if len(labels) == 0 {
+		return nil, nil, NewArgError("labels", "cannot be empty")
+	}
+	return svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		merged := make(map[string]string, len(pool.Labels)+len(labels))
+		for k, v := range pool.Labels {
+			merged[k] = v
+		}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		if stringMapsEqual(merged, pool.Labels) {
+			return nil
+		}
+		return &KubernetesNodePoolUpdateRequest{Labels: merged}
+	}, kubernetesNodePoolPatchRetries)
+}
+
+// RemoveLabels removes the labels with the given keys from an existing node
+// pool, keeping its other labels. Nothing is updated if the node pool has none
+// of the labels. As an empty set of labels is left out of update requests,
+// the last labels of a node pool cannot be removed: an error matching
+// ErrKubernetesClearUnsupported is returned instead, without an update.
+func (svc *KubernetesServiceOp) RemoveLabels(ctx context.Context, clusterID, poolID string, keys ...string) (*KubernetesNodePool, *Response, error) {
+	if len(keys) == 0 {
+		return nil, nil, NewArgError("keys", "cannot be empty")
+	}
+	var clearErr error
+	pool, resp, err := svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		clearErr = nil
+		remaining := make(map[string]string, len(pool.Labels))
+		for k, v := range pool.Labels {
+			remaining[k] = v
+		}
+		for _, k := range keys {
+			delete(remaining, k)
+		}
+		if len(remaining) == len(pool.Labels) {
+			return nil
+		}
+		if len(remaining) == 0 {
+			clearErr = fmt.Errorf("node pool %s labels: %w", poolID, ErrKubernetesClearUnsupported)
+			return nil
+		}
+		return &KubernetesNodePoolUpdateRequest{Labels: remaining}
+	}, kubernetesNodePoolPatchRetries)
+	if clearErr != nil {
+		return nil, resp, clearErr
+	}
+	return pool, resp, err
+}
+
+// AddTags adds tags to an existing node pool, keeping its other tags. Unlike
+// UpdateNodePool, which replaces all tags, the result is the union of the
+// current and the given tags, without duplicates and with the current tags
+// first. The k8s and k8s:* tags added by the API are not sent back. Nothing is
+// updated if the node pool already has all the tags.
+func (svc *KubernetesServiceOp) AddTags(ctx context.Context, clusterID, poolID string, tags ...string) (*KubernetesNodePool, *Response, error) {
+	if len(tags) == 0 {
+		return nil, nil, NewArgError("tags", "cannot be empty")
+	}
+	return svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		current := userTags(pool.Tags)
+		seen := make(map[string]bool, len(current)+len(tags))
+		merged := make([]string, 0, len(current)+len(tags))
+		for _, tag := range current {
+			if !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+		added := false
+		for _, tag := range tags {
+			if !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+				added = true
+			}
+		}
+		if !added {
+			return nil
+		}
+		return &KubernetesNodePoolUpdateRequest{Tags: merged}
+	}, kubernetesNodePoolPatchRetries)
+}
+
+// RemoveTags removes tags from an existing node pool, keeping the order of its
+// other tags. The k8s and k8s:* tags added by the API are not sent back.
+// Nothing is updated if the node pool has none of the tags. As an empty list
+// of tags is left out of update requests, the last user tags of a node pool
+// cannot be removed: an error matching ErrKubernetesClearUnsupported is
+// returned instead, without an update.
+func (svc *KubernetesServiceOp) RemoveTags(ctx context.Context, clusterID, poolID string, tags ...string) (*KubernetesNodePool, *Response, error) {
+	if len(tags) == 0 {
+		return nil, nil, NewArgError("tags", "cannot be empty")
+	}
+	remove := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		remove[tag] = true
+	}
+	var clearErr error
+	pool, resp, err := svc.UpdateNodePoolWithRetry(ctx, clusterID, poolID, func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		clearErr = nil
+		current := userTags(pool.Tags)
+		seen := make(map[string]bool, len(current))
+		remaining := make([]string, 0, len(current))
+		removed := false
+		for _, tag := range current {
+			if remove[tag] {
+				removed = true
+				continue
+			}
+			if !seen[tag] {
+				seen[tag] = true
+				remaining = append(remaining, tag)
+			}
+		}
+		if !removed {
+			return nil
+		}
+		if len(remaining) == 0 {
+			clearErr = fmt.Errorf("node pool %s tags: %w", poolID, ErrKubernetesClearUnsupported)
+			return nil
+		}
+		return &KubernetesNodePoolUpdateRequest{Tags: remaining}
+	}, kubernetesNodePoolPatchRetries)
+	if clearErr != nil {
+		return nil, resp, clearErr
+	}
+	return pool, resp, err
+}
+
+// EnableAutoScale turns on autoscaling for an existing node pool, letting it
+// scale between min and max nodes.
+func (svc *KubernetesServiceOp) EnableAutoScale(ctx context.Context, clusterID, poolID string, min, max int) (*KubernetesNodePool, *Response, error) {
+	if min < 0 {
+		return nil, nil, NewArgError("min", "cannot be less than 0")
+	}
+	if min > max {
+		return nil, nil, NewArgError("max", "cannot be less than min")
+	}
+	update := &KubernetesNodePoolUpdateRequest{
+		AutoScale: PtrTo(true),
+		MinNodes:  PtrTo(min),
+		MaxNodes:  PtrTo(max),
+	}
+	return svc.UpdateNodePool(ctx, clusterID, poolID, update)
+}
+
+// DisableAutoScale turns off autoscaling for an existing node pool. The pool
+// keeps its current node count.
+func (svc *KubernetesServiceOp) DisableAutoScale(ctx context.Context, clusterID, poolID string) (*KubernetesNodePool, *Response, error) {
+	update := &KubernetesNodePoolUpdateRequest{
+		AutoScale: PtrTo(false),
+	}
+	return svc.UpdateNodePool(ctx, clusterID, poolID, update)
+}
+
 // RecycleNodePoolNodes is DEPRECATED please use DeleteNode
 // The method will be removed in godo 2.0.
 func (svc *KubernetesServiceOp) RecycleNodePoolNodes(ctx context.Context, clusterID, poolID string, recycle *KubernetesNodePoolRecycleNodesRequest) (*Response, error) {
@@ -842,7 +2183,18 @@ func (svc *KubernetesServiceOp) RecycleNodePoolNodes(ctx context.Context, cluste
 }
 
 // DeleteNodePool deletes a node pool, and subsequently all the nodes in that pool.
+// If the client was configured with WithKubernetesProtectedNodePoolLabels, node
+// pools carrying a protected label are not deleted.
 func (svc *KubernetesServiceOp) DeleteNodePool(ctx context.Context, clusterID, poolID string) (*Response, error) {
+	if resp, err := svc.checkNodePoolNotProtected(ctx, clusterID, poolID); err != nil {
+		return resp, err
+	}
+	return svc.ForceDeleteNodePool(ctx, clusterID, poolID)
+}
+
+// ForceDeleteNodePool deletes a node pool like DeleteNodePool, even if it
+// carries a protected label.
+func (svc *KubernetesServiceOp) ForceDeleteNodePool(ctx context.Context, clusterID, poolID string) (*Response, error) {
 	path := fmt.Sprintf("%s/%s/node_pools/%s", kubernetesClustersPath, clusterID, poolID)
 	req, err := svc.client.NewRequest(ctx, http.MethodDelete, path, nil)
 	if err != nil {
@@ -855,6 +2207,25 @@ func (svc *KubernetesServiceOp) DeleteNodePool(ctx context.Context, clusterID, p
 	return resp, nil
 }
 
+// DeleteNodePools deletes several node pools of a Kubernetes cluster, one after
+// the other. A failed deletion does not stop the remaining ones; the failures
+// are joined into the returned error, naming the node pools that could not be
+// deleted. The response of the last request is returned.
+func (svc *KubernetesServiceOp) DeleteNodePools(ctx context.Context, clusterID string, poolIDs []string) (*Response, error) {
+	var (
+		resp *Response
+		errs []error
+	)
+	for _, poolID := range poolIDs {
+		var err error
+		resp, err = svc.DeleteNodePool(ctx, clusterID, poolID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deleting node pool %s: %w", poolID, err))
+		}
+	}
+	return resp, errors.Join(errs...)
+}
+
 // DeleteNode deletes a specific node in a node pool.
 func (svc *KubernetesServiceOp) DeleteNode(ctx context.Context, clusterID, poolID, nodeID string, deleteReq *KubernetesNodeDeleteRequest) (*Response, error) {
 	path := fmt.Sprintf("%s/%s/node_pools/%s/nodes/%s", kubernetesClustersPath, clusterID, poolID, nodeID)
@@ -896,15 +2267,75 @@ func (svc *KubernetesServiceOp) GetOptions(ctx context.Context) (*KubernetesOpti
 		return nil, nil, err
 	}
 	root := new(kubernetesOptionsRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.doRead(ctx, req, root)
 	if err != nil {
 		return nil, resp, err
 	}
 	return root.Options, resp, nil
 }
 
-// AddRegistry integrates docr registry with all the specified clusters
+// CachedOptions returns the options of the Kubernetes service like
+// GetOptions, but serves the result of the last successful call for ttl before
+// fetching them again. It is safe for concurrent use; concurrent callers share
+// a single fetch, and those waiting for it return early when their own ctx is
+// done. The returned options are shared between callers and must not be
+// modified.
+func (svc *KubernetesServiceOp) CachedOptions(ctx context.Context, ttl time.Duration) (*KubernetesOptions, error) {
+	if ttl <= 0 {
+		return nil, NewArgError("ttl", "must be greater than 0")
+	}
+
+	for {
+		svc.optionsMu.Lock()
+		if svc.options != nil && svc.now().Before(svc.optionsExpiresAt) {
+			options := svc.options
+			svc.optionsMu.Unlock()
+			return options, nil
+		}
+		f := svc.optionsFetch
+		if f == nil {
+			f = &kubernetesOptionsFetch{done: make(chan struct{})}
+			svc.optionsFetch = f
+			svc.optionsMu.Unlock()
+			return svc.fetchOptions(ctx, f, ttl)
+		}
+		svc.optionsMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-f.done:
+		}
+		// A fetch that failed because the context of its caller was done
+		// says nothing about the API, so try again with our own context.
+		if f.err == nil || !(errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+			return f.options, f.err
+		}
+	}
+}
+
+// fetchOptions performs the fetch f on behalf of all callers of CachedOptions
+// and caches its result for ttl if it succeeds.
+func (svc *KubernetesServiceOp) fetchOptions(ctx context.Context, f *kubernetesOptionsFetch, ttl time.Duration) (*KubernetesOptions, error) {
+	f.options, _, f.err = svc.GetOptions(ctx)
+
+	svc.optionsMu.Lock()
+	if f.err == nil {
+		svc.options, svc.optionsExpiresAt = f.options, svc.now().Add(ttl)
+	}
+	svc.optionsFetch = nil
+	svc.optionsMu.Unlock()
+	close(f.done)
+
+	return f.options, f.err
+}
+
+// AddRegistry integrates docr registry with all the specified clusters. The
+// request is checked with Validate before it is sent.
 func (svc *KubernetesServiceOp) AddRegistry(ctx context.Context, req *KubernetesClusterRegistryRequest) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("%s/registry", kubernetesBasePath)
 	request, err := svc.client.NewRequest(ctx, http.MethodPost, path, req)
 	if err != nil {
@@ -917,8 +2348,12 @@ func (svc *KubernetesServiceOp) AddRegistry(ctx context.Context, req *Kubernetes
 	return resp, nil
 }
 
-// RemoveRegistry removes docr registry support for all the specified clusters
+// RemoveRegistry removes docr registry support for all the specified clusters. The
+// request is checked with Validate before it is sent.
 func (svc *KubernetesServiceOp) RemoveRegistry(ctx context.Context, req *KubernetesClusterRegistryRequest) (*Response, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	path := fmt.Sprintf("%s/registry", kubernetesBasePath)
 	request, err := svc.client.NewRequest(ctx, http.MethodDelete, path, req)
 	if err != nil {
@@ -931,6 +2366,55 @@ func (svc *KubernetesServiceOp) RemoveRegistry(ctx context.Context, req *Kuberne
 	return resp, nil
 }
 
+// Possible values returned by RegistryStatus.
+const (
+	KubernetesRegistryStatusEnabled  = "enabled"
+	KubernetesRegistryStatusDisabled = "disabled"
+)
+
+// RegistryStatus reports whether the registry integration is enabled for the
+// cluster. The API does not distinguish a pending integration from an active
+// one, so the status is derived from the cluster's RegistryEnabled field,
+// which may lag behind AddRegistry and RemoveRegistry.
+func (svc *KubernetesServiceOp) RegistryStatus(ctx context.Context, clusterID string) (string, *Response, error) {
+	enabled, resp, err := svc.IsRegistryEnabled(ctx, clusterID)
+	if err != nil {
+		return "", resp, err
+	}
+	if enabled {
+		return KubernetesRegistryStatusEnabled, resp, nil
+	}
+	return KubernetesRegistryStatusDisabled, resp, nil
+}
+
+// IsRegistryEnabled reports whether the registry integration is enabled for the
+// cluster, based on the cluster's RegistryEnabled field.
+func (svc *KubernetesServiceOp) IsRegistryEnabled(ctx context.Context, clusterID string) (bool, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return false, resp, err
+	}
+	return cluster.RegistryEnabled, resp, nil
+}
+
+// ListRegistryIntegratedClusters returns the IDs of all clusters that have the
+// registry integration enabled. The API has no dedicated endpoint for this, so
+// every cluster is listed and filtered on its RegistryEnabled field.
+func (svc *KubernetesServiceOp) ListRegistryIntegratedClusters(ctx context.Context) ([]string, *Response, error) {
+	clusters, resp, err := svc.ListClustersAll(ctx, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ids []string
+	for _, cluster := range clusters {
+		if cluster.RegistryEnabled {
+			ids = append(ids, cluster.ID)
+		}
+	}
+	return ids, resp, nil
+}
+
 type runClusterlintRoot struct {
 	RunID string `json:"run_id"`
 }
@@ -954,7 +2438,9 @@ type clusterlintDiagnosticsRoot struct {
 	Diagnostics []*ClusterlintDiagnostic
 }
 
-// GetClusterlintResults fetches the diagnostics after clusterlint run completes
+// GetClusterlintResults fetches the diagnostics after clusterlint run completes.
+// If req is nil or its RunId is empty, the results of the most recent run are
+// returned.
 func (svc *KubernetesServiceOp) GetClusterlintResults(ctx context.Context, clusterID string, req *KubernetesGetClusterlintRequest) ([]*ClusterlintDiagnostic, *Response, error) {
 	path := fmt.Sprintf("%s/%s/clusterlint", kubernetesClustersPath, clusterID)
 	if req != nil {
@@ -978,3 +2464,9 @@ func (svc *KubernetesServiceOp) GetClusterlintResults(ctx context.Context, clust
 	}
 	return root.Diagnostics, resp, nil
 }
+
+// GetLatestClusterlintResults fetches the diagnostics of the most recent
+// clusterlint run of the cluster.
+func (svc *KubernetesServiceOp) GetLatestClusterlintResults(ctx context.Context, clusterID string) ([]*ClusterlintDiagnostic, *Response, error) {
+	return svc.GetClusterlintResults(ctx, clusterID, nil)
+}
@@ -0,0 +1,87 @@
+package godo
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Tag prefixes used to record backup metadata on Kubernetes clusters. DOKS
+// does not back up clusters itself; these tags let tools such as Velero record
+// which tool backs up a cluster and when it last did.
+const (
+	// KubernetesBackupToolTagPrefix prefixes the tag naming the backup tool,
+	// e.g. "backup:velero".
+	KubernetesBackupToolTagPrefix = "backup:"
+
+	// KubernetesLastBackupTagPrefix prefixes the tag holding the time of the
+	// last backup in RFC 3339 format, e.g. "last-backup:2024-06-01T09:00:00Z".
+	KubernetesLastBackupTagPrefix = "last-backup:"
+)
+
+// BackupTool returns the backup tool recorded in the cluster's tags, or an
+// empty string if there is none.
+func (kc *KubernetesCluster) BackupTool() string {
+	for _, tag := range kc.Tags {
+		if tool, ok := strings.CutPrefix(tag, KubernetesBackupToolTagPrefix); ok {
+			return tool
+		}
+	}
+	return ""
+}
+
+// LastBackupTime returns the time of the last backup recorded in the
+// cluster's tags. If there are several, the most recent is returned. It
+// returns false if no valid backup time is recorded.
+func (kc *KubernetesCluster) LastBackupTime() (time.Time, bool) {
+	var last time.Time
+	found := false
+	for _, tag := range kc.Tags {
+		value, ok := strings.CutPrefix(tag, KubernetesLastBackupTagPrefix)
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		if !found || t.After(last) {
+			last, found = t, true
+		}
+	}
+	return last, found
+}
+
+// WithKubernetesBackupTags returns a copy of tags in which the backup tags are
+// replaced by ones recording tool and the backup time at. The time is stored
+// in UTC with second precision. An empty tool leaves the tool tag unchanged.
+func WithKubernetesBackupTags(tags []string, tool string, at time.Time) []string {
+	updated := make([]string, 0, len(tags)+2)
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, KubernetesLastBackupTagPrefix) {
+			continue
+		}
+		if tool != "" && strings.HasPrefix(tag, KubernetesBackupToolTagPrefix) {
+			continue
+		}
+		updated = append(updated, tag)
+	}
+	if tool != "" {
+		updated = append(updated, KubernetesBackupToolTagPrefix+tool)
+	}
+	return append(updated, KubernetesLastBackupTagPrefix+at.UTC().Truncate(time.Second).Format(time.RFC3339))
+}
+
+// SetClusterBackupMetadata records a backup of the cluster made with tool at
+// the given time by updating the cluster's backup tags. Other tags are kept.
+// The cluster is fetched first and its other settings are sent back
+// unchanged, so that they are not cleared by the update.
+func (svc *KubernetesServiceOp) SetClusterBackupMetadata(ctx context.Context, clusterID, tool string, at time.Time) (*KubernetesCluster, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+	update := cluster.ToUpdateRequest()
+	update.Tags = WithKubernetesBackupTags(update.Tags, tool, at)
+	return svc.Update(ctx, clusterID, update)
+}
@@ -0,0 +1,76 @@
+package godo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesCluster_BackupMetadata(t *testing.T) {
+	cluster := &KubernetesCluster{Tags: []string{
+		"k8s",
+		"backup:velero",
+		"last-backup:2024-06-01T09:00:00Z",
+		"last-backup:2024-06-02T09:00:00Z",
+		"last-backup:yesterday",
+	}}
+	assert.Equal(t, "velero", cluster.BackupTool())
+	last, ok := cluster.LastBackupTime()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 6, 2, 9, 0, 0, 0, time.UTC), last)
+
+	empty := &KubernetesCluster{Tags: []string{"k8s", "env:prod"}}
+	assert.Equal(t, "", empty.BackupTool())
+	_, ok = empty.LastBackupTime()
+	assert.False(t, ok)
+}
+
+func TestWithKubernetesBackupTags(t *testing.T) {
+	at := time.Date(2024, 6, 3, 11, 30, 15, 123, time.FixedZone("CEST", 2*60*60))
+	tags := []string{"env:prod", "backup:restic", "last-backup:2024-06-01T09:00:00Z"}
+
+	got := WithKubernetesBackupTags(tags, "velero", at)
+	assert.Equal(t, []string{"env:prod", "backup:velero", "last-backup:2024-06-03T09:30:15Z"}, got)
+	assert.Equal(t, []string{"env:prod", "backup:restic", "last-backup:2024-06-01T09:00:00Z"}, tags, "input must not be modified")
+
+	got = WithKubernetesBackupTags(tags, "", at)
+	assert.Equal(t, []string{"env:prod", "backup:restic", "last-backup:2024-06-03T09:30:15Z"}, got)
+
+	cluster := &KubernetesCluster{Tags: got}
+	last, ok := cluster.LastBackupTime()
+	require.True(t, ok)
+	assert.True(t, last.Equal(at.Truncate(time.Second)))
+}
+
+func TestKubernetesClusters_SetClusterBackupMetadata(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "antoine", "tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod"], "maintenance_policy": {"start_time": "04:00", "day": "sunday"}, "auto_upgrade": true}}`)
+		case http.MethodPut:
+			v := new(KubernetesClusterUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, []string{"env:prod", "backup:velero", "last-backup:2024-06-01T09:00:00Z"}, v.Tags)
+			assert.Equal(t, "antoine", v.Name)
+			assert.Equal(t, &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDaySunday}, v.MaintenancePolicy)
+			assert.Equal(t, PtrTo(true), v.AutoUpgrade)
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod", "backup:velero", "last-backup:2024-06-01T09:00:00Z"]}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.SetClusterBackupMetadata(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "velero", time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "velero", got.BackupTool())
+}
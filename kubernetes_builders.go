@@ -0,0 +1,155 @@
+package godo
+
+// KubernetesNodePoolUpdateBuilder builds a KubernetesNodePoolUpdateRequest,
+// taking care of the pointer fields that distinguish an unchanged value from a
+// zero value. Create one with NewNodePoolUpdate.
+type KubernetesNodePoolUpdateBuilder struct {
+	req KubernetesNodePoolUpdateRequest
+}
+
+// NewNodePoolUpdate returns a builder for a node pool update request that
+// changes nothing until its methods are called.
+func NewNodePoolUpdate() *KubernetesNodePoolUpdateBuilder {
+	return &KubernetesNodePoolUpdateBuilder{}
+}
+
+// SetCount sets the number of nodes of the node pool. A count of 0 is sent to
+// the API rather than being left out.
+func (b *KubernetesNodePoolUpdateBuilder) SetCount(count int) *KubernetesNodePoolUpdateBuilder {
+	b.req.Count = PtrTo(count)
+	return b
+}
+
+// SetAutoScale enables autoscaling of the node pool between min and max nodes.
+func (b *KubernetesNodePoolUpdateBuilder) SetAutoScale(min, max int) *KubernetesNodePoolUpdateBuilder {
+	b.req.AutoScale = PtrTo(true)
+	b.req.MinNodes = PtrTo(min)
+	b.req.MaxNodes = PtrTo(max)
+	return b
+}
+
+// DisableAutoScale disables autoscaling of the node pool.
+func (b *KubernetesNodePoolUpdateBuilder) DisableAutoScale() *KubernetesNodePoolUpdateBuilder {
+	b.req.AutoScale = PtrTo(false)
+	b.req.MinNodes = nil
+	b.req.MaxNodes = nil
+	return b
+}
+
+// AddLabel adds a label to the request. Labels in an update replace all labels
+// of the node pool, so every label the node pool should keep must be added.
+func (b *KubernetesNodePoolUpdateBuilder) AddLabel(key, value string) *KubernetesNodePoolUpdateBuilder {
+	if b.req.Labels == nil {
+		b.req.Labels = make(map[string]string)
+	}
+	b.req.Labels[key] = value
+	return b
+}
+
+// AddTaint adds a taint to the request. Taints in an update replace all taints
+// of the node pool.
+func (b *KubernetesNodePoolUpdateBuilder) AddTaint(taint Taint) *KubernetesNodePoolUpdateBuilder {
+	var taints []Taint
+	if b.req.Taints != nil {
+		taints = *b.req.Taints
+	}
+	taints = append(taints, taint)
+	b.req.Taints = &taints
+	return b
+}
+
+// ClearTaints removes all taints from the node pool by sending an empty list.
+func (b *KubernetesNodePoolUpdateBuilder) ClearTaints() *KubernetesNodePoolUpdateBuilder {
+	b.req.Taints = &[]Taint{}
+	return b
+}
+
+// Build returns the update request. The builder can be reused afterwards
+// without affecting the returned request.
+func (b *KubernetesNodePoolUpdateBuilder) Build() *KubernetesNodePoolUpdateRequest {
+	req := b.req
+	if b.req.Labels != nil {
+		req.Labels = make(map[string]string, len(b.req.Labels))
+		for k, v := range b.req.Labels {
+			req.Labels[k] = v
+		}
+	}
+	if b.req.Taints != nil {
+		taints := append([]Taint{}, *b.req.Taints...)
+		req.Taints = &taints
+	}
+	return &req
+}
+
+// KubernetesNodePoolCreateBuilder builds a KubernetesNodePoolCreateRequest.
+// Create one with NewNodePoolBuilder.
+type KubernetesNodePoolCreateBuilder struct {
+	req KubernetesNodePoolCreateRequest
+}
+
+// NewNodePoolBuilder returns a builder for a request to create a node pool
+// with the given name and Droplet size slug.
+func NewNodePoolBuilder(name, size string) *KubernetesNodePoolCreateBuilder {
+	return &KubernetesNodePoolCreateBuilder{
+		req: KubernetesNodePoolCreateRequest{Name: name, Size: size},
+	}
+}
+
+// WithCount sets the number of nodes of the node pool.
+func (b *KubernetesNodePoolCreateBuilder) WithCount(count int) *KubernetesNodePoolCreateBuilder {
+	b.req.Count = count
+	return b
+}
+
+// WithAutoScale enables autoscaling of the node pool between min and max
+// nodes.
+func (b *KubernetesNodePoolCreateBuilder) WithAutoScale(min, max int) *KubernetesNodePoolCreateBuilder {
+	b.req.AutoScale = true
+	b.req.MinNodes = min
+	b.req.MaxNodes = max
+	return b
+}
+
+// WithLabels adds labels to the node pool, overwriting labels with the same
+// key that were added before.
+func (b *KubernetesNodePoolCreateBuilder) WithLabels(labels map[string]string) *KubernetesNodePoolCreateBuilder {
+	if b.req.Labels == nil {
+		b.req.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		b.req.Labels[k] = v
+	}
+	return b
+}
+
+// WithTaints adds taints to the node pool.
+func (b *KubernetesNodePoolCreateBuilder) WithTaints(taints ...Taint) *KubernetesNodePoolCreateBuilder {
+	b.req.Taints = append(b.req.Taints, taints...)
+	return b
+}
+
+// WithTags adds tags to the node pool.
+func (b *KubernetesNodePoolCreateBuilder) WithTags(tags ...string) *KubernetesNodePoolCreateBuilder {
+	b.req.Tags = append(b.req.Tags, tags...)
+	return b
+}
+
+// Build checks the request with Validate and returns it. The builder can be
+// reused afterwards without affecting the returned request.
+func (b *KubernetesNodePoolCreateBuilder) Build() (*KubernetesNodePoolCreateRequest, error) {
+	if err := b.req.Validate(); err != nil {
+		return nil, err
+	}
+	req := b.req
+	req.Tags = copyStrings(b.req.Tags)
+	if b.req.Labels != nil {
+		req.Labels = make(map[string]string, len(b.req.Labels))
+		for k, v := range b.req.Labels {
+			req.Labels[k] = v
+		}
+	}
+	if b.req.Taints != nil {
+		req.Taints = append([]Taint{}, b.req.Taints...)
+	}
+	return &req, nil
+}
@@ -0,0 +1,147 @@
+package godo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNodePoolUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *KubernetesNodePoolUpdateBuilder
+		want    string
+	}{
+		{
+			name:    "empty",
+			builder: NewNodePoolUpdate(),
+			want:    `{}`,
+		},
+		{
+			name:    "count",
+			builder: NewNodePoolUpdate().SetCount(3),
+			want:    `{"count": 3}`,
+		},
+		{
+			name:    "zero count",
+			builder: NewNodePoolUpdate().SetCount(0),
+			want:    `{"count": 0}`,
+		},
+		{
+			name:    "autoscale",
+			builder: NewNodePoolUpdate().SetAutoScale(0, 5),
+			want:    `{"auto_scale": true, "min_nodes": 0, "max_nodes": 5}`,
+		},
+		{
+			name:    "disable autoscale",
+			builder: NewNodePoolUpdate().SetAutoScale(1, 5).DisableAutoScale(),
+			want:    `{"auto_scale": false}`,
+		},
+		{
+			name:    "labels",
+			builder: NewNodePoolUpdate().AddLabel("priority", "high").AddLabel("service", "backend"),
+			want:    `{"labels": {"priority": "high", "service": "backend"}}`,
+		},
+		{
+			name:    "add taint",
+			builder: NewNodePoolUpdate().AddTaint(Taint{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}),
+			want:    `{"taints": [{"Key": "dedicated", "Value": "gpu", "Effect": "NoSchedule"}]}`,
+		},
+		{
+			name:    "clear taints",
+			builder: NewNodePoolUpdate().AddTaint(Taint{Key: "dedicated", Effect: TaintEffectNoSchedule}).ClearTaints(),
+			want:    `{"taints": []}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.builder.Build())
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewNodePoolUpdate_BuildIsIndependent(t *testing.T) {
+	b := NewNodePoolUpdate().AddLabel("priority", "high").ClearTaints()
+	req := b.Build()
+
+	b.AddLabel("service", "backend").AddTaint(Taint{Key: "dedicated", Effect: TaintEffectNoSchedule})
+
+	assert.Equal(t, map[string]string{"priority": "high"}, req.Labels)
+	assert.Empty(t, *req.Taints)
+}
+
+func TestNewNodePoolBuilder(t *testing.T) {
+	got, err := NewNodePoolBuilder("workers", "s-2vcpu-4gb").
+		WithCount(3).
+		WithAutoScale(2, 5).
+		WithLabels(map[string]string{"service": "backend"}).
+		WithLabels(map[string]string{"priority": "high"}).
+		WithTaints(Taint{Key: "dedicated", Value: "backend", Effect: TaintEffectNoSchedule}).
+		WithTags("team:payments").
+		WithTags("env:prod").
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesNodePoolCreateRequest{
+		Name:      "workers",
+		Size:      "s-2vcpu-4gb",
+		Count:     3,
+		Tags:      []string{"team:payments", "env:prod"},
+		Labels:    map[string]string{"service": "backend", "priority": "high"},
+		Taints:    []Taint{{Key: "dedicated", Value: "backend", Effect: TaintEffectNoSchedule}},
+		AutoScale: true,
+		MinNodes:  2,
+		MaxNodes:  5,
+	}, got)
+}
+
+func TestNewNodePoolBuilder_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *KubernetesNodePoolCreateBuilder
+		wantErr error
+	}{
+		{
+			name:    "min above max",
+			builder: NewNodePoolBuilder("workers", "s-2vcpu-4gb").WithAutoScale(5, 2),
+			wantErr: NewArgError("MinNodes", "cannot be greater than MaxNodes"),
+		},
+		{
+			name:    "negative min",
+			builder: NewNodePoolBuilder("workers", "s-2vcpu-4gb").WithAutoScale(-1, 2),
+			wantErr: NewArgError("MinNodes", "cannot be less than 0"),
+		},
+		{
+			name:    "count outside bounds",
+			builder: NewNodePoolBuilder("workers", "s-2vcpu-4gb").WithCount(6).WithAutoScale(1, 5),
+			wantErr: NewArgError("Count", "must be between MinNodes and MaxNodes"),
+		},
+		{
+			name:    "negative count",
+			builder: NewNodePoolBuilder("workers", "s-2vcpu-4gb").WithCount(-1),
+			wantErr: NewArgError("Count", "cannot be less than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			require.Equal(t, tt.wantErr, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestNewNodePoolBuilder_BuildIsIndependent(t *testing.T) {
+	b := NewNodePoolBuilder("workers", "s-2vcpu-4gb").WithCount(1).WithTags("env:prod").WithLabels(map[string]string{"priority": "high"})
+	req, err := b.Build()
+	require.NoError(t, err)
+
+	b.WithTags("team:payments").WithLabels(map[string]string{"service": "backend"}).WithTaints(Taint{Key: "dedicated", Effect: TaintEffectNoSchedule})
+
+	assert.Equal(t, []string{"env:prod"}, req.Tags)
+	assert.Equal(t, map[string]string{"priority": "high"}, req.Labels)
+	assert.Empty(t, req.Taints)
+}
@@ -0,0 +1,264 @@
+package godo
+
+import (
+	"context"
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// ClusterlintSeverity is the severity of a clusterlint diagnostic.
+type ClusterlintSeverity string
+
+// Possible clusterlint severities, from least to most severe.
+const (
+	ClusterlintSeveritySuggestion = ClusterlintSeverity("suggestion")
+	ClusterlintSeverityWarning    = ClusterlintSeverity("warning")
+	ClusterlintSeverityError      = ClusterlintSeverity("error")
+)
+
+// level ranks the severity. Unknown severities rank below all known ones.
+func (s ClusterlintSeverity) level() int {
+	switch s {
+	case ClusterlintSeveritySuggestion:
+		return 1
+	case ClusterlintSeverityWarning:
+		return 2
+	case ClusterlintSeverityError:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is at least as severe as min.
+func (s ClusterlintSeverity) AtLeast(min ClusterlintSeverity) bool {
+	return s.level() >= min.level()
+}
+
+// FilterClusterlintDiagnostics returns the diagnostics whose severity is at or
+// above minSeverity. Diagnostics with an unknown severity are only kept if
+// minSeverity is unknown as well.
+func FilterClusterlintDiagnostics(diags []*ClusterlintDiagnostic, minSeverity ClusterlintSeverity) []*ClusterlintDiagnostic {
+	var filtered []*ClusterlintDiagnostic
+	for _, d := range diags {
+		if ClusterlintSeverity(d.Severity).AtLeast(minSeverity) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// ClusterlintUnowned is the key under which GroupClusterlintDiagnosticsByOwner
+// groups diagnostics of objects without owners.
+const ClusterlintUnowned = "unowned"
+
+// GroupClusterlintDiagnosticsByOwner groups diagnostics by the owners of their
+// objects, keyed by owner kind and name, e.g. "Deployment/web". A diagnostic
+// whose object has several owners is listed under each of them, and those
+// without owners are grouped under ClusterlintUnowned.
+func GroupClusterlintDiagnosticsByOwner(diags []*ClusterlintDiagnostic) map[string][]*ClusterlintDiagnostic {
+	groups := make(map[string][]*ClusterlintDiagnostic)
+	for _, d := range diags {
+		var owners []*ClusterlintOwner
+		if d.Object != nil {
+			owners = d.Object.Owners
+		}
+		if len(owners) == 0 {
+			groups[ClusterlintUnowned] = append(groups[ClusterlintUnowned], d)
+			continue
+		}
+		for _, owner := range owners {
+			key := owner.Kind + "/" + owner.Name
+			groups[key] = append(groups[key], d)
+		}
+	}
+	return groups
+}
+
+// ClusterlintNoObject is the key under which
+// GroupClusterlintDiagnosticsByObject groups diagnostics without an object.
+const ClusterlintNoObject = "no-object"
+
+// GroupClusterlintDiagnosticsByObject groups diagnostics by the object they
+// refer to, keyed by namespace, kind and name, e.g. "default/Pod/web-1". The
+// namespace is empty for cluster-scoped objects, e.g. "/Node/pool-a-1".
+// Diagnostics without an object are grouped under ClusterlintNoObject.
+func GroupClusterlintDiagnosticsByObject(diags []*ClusterlintDiagnostic) map[string][]*ClusterlintDiagnostic {
+	groups := make(map[string][]*ClusterlintDiagnostic)
+	for _, d := range diags {
+		key := clusterlintObjectKey(d.Object)
+		groups[key] = append(groups[key], d)
+	}
+	return groups
+}
+
+// clusterlintObjectKey returns the namespace/kind/name key of o, or
+// ClusterlintNoObject if o is nil.
+func clusterlintObjectKey(o *ClusterlintObject) string {
+	if o == nil {
+		return ClusterlintNoObject
+	}
+	return o.Namespace + "/" + o.Kind + "/" + o.Name
+}
+
+// GroupClusterlintDiagnosticsByCheck groups diagnostics by the name of the
+// check that reported them.
+func GroupClusterlintDiagnosticsByCheck(diags []*ClusterlintDiagnostic) map[string][]*ClusterlintDiagnostic {
+	groups := make(map[string][]*ClusterlintDiagnostic)
+	for _, d := range diags {
+		groups[d.CheckName] = append(groups[d.CheckName], d)
+	}
+	return groups
+}
+
+// KubernetesClusterlintCatalog lists the names of the clusterlint groups and
+// checks that can be used to filter a clusterlint run.
+type KubernetesClusterlintCatalog struct {
+	Groups []string
+	Checks []string
+}
+
+// knownClusterlintCatalog lists the clusterlint groups and checks known to
+// this version of godo. It mirrors the check registry of
+// github.com/digitalocean/clusterlint v0.3.0 (the names passed to
+// checks.Register) and must be kept in sync with it, as Validate rejects any
+// name missing here. It is only handed out as copies, see
+// KnownClusterlintCatalog.
+var knownClusterlintCatalog = KubernetesClusterlintCatalog{
+	Groups: []string{
+		"basic",
+		"doks",
+		"security",
+		"workload-health",
+	},
+	Checks: []string{
+		"admission-controller-webhook",
+		"admission-controller-webhook-replacement",
+		"admission-controller-webhook-timeout",
+		"bare-pods",
+		"cronjob-concurrency",
+		"default-namespace",
+		"dobs-pod-owner",
+		"fully-qualified-image",
+		"hostpath-volume",
+		"latest-tag",
+		"node-labels-and-taints",
+		"node-name-pod-selector",
+		"pod-selector",
+		"pod-state",
+		"privileged-containers",
+		"resource-requirements",
+		"run-as-non-root",
+		"unused-config-map",
+		"unused-pv",
+		"unused-pvc",
+		"unused-secret",
+	},
+}
+
+// KnownClusterlintCatalog returns a copy of the clusterlint groups and checks
+// known to this version of godo. The API has no endpoint to list them, so the
+// catalog may lag behind the checks DigitalOcean runs; append to the returned
+// copy to allow newer names.
+func KnownClusterlintCatalog() KubernetesClusterlintCatalog {
+	return KubernetesClusterlintCatalog{
+		Groups: append([]string(nil), knownClusterlintCatalog.Groups...),
+		Checks: append([]string(nil), knownClusterlintCatalog.Checks...),
+	}
+}
+
+// GetClusterlintCatalog returns the names of the clusterlint groups and checks
+// that can be used to filter a clusterlint run. As the API has no endpoint to
+// list them, it makes no request and returns KnownClusterlintCatalog, with a
+// nil *Response.
+func (svc *KubernetesServiceOp) GetClusterlintCatalog(ctx context.Context) (KubernetesClusterlintCatalog, *Response, error) {
+	return KnownClusterlintCatalog(), nil, nil
+}
+
+// clusterlintJUnitSuite is the JUnit XML test suite written by
+// ClusterlintDiagnosticsToJUnit.
+type clusterlintJUnitSuite struct {
+	XMLName   xml.Name                    `xml:"testsuite"`
+	Name      string                      `xml:"name,attr"`
+	Tests     int                         `xml:"tests,attr"`
+	Failures  int                         `xml:"failures,attr"`
+	TestCases []*clusterlintJUnitTestCase `xml:"testcase"`
+}
+
+type clusterlintJUnitTestCase struct {
+	Name      string                   `xml:"name,attr"`
+	ClassName string                   `xml:"classname,attr"`
+	Failure   *clusterlintJUnitFailure `xml:"failure,omitempty"`
+	SystemOut string                   `xml:"system-out,omitempty"`
+}
+
+type clusterlintJUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ClusterlintDiagnosticsToJUnit renders diagnostics as a JUnit XML report
+// with a test suite named suiteName and one test case per check, sorted by
+// check name. A check fails if it reported a diagnostic with the warning or
+// error severity; its failure lists the messages of those diagnostics, one
+// per line and prefixed with the object they refer to. Diagnostics with other
+// severities, such as suggestions, are listed in the output of the test case
+// without failing it.
+func ClusterlintDiagnosticsToJUnit(diags []*ClusterlintDiagnostic, suiteName string) ([]byte, error) {
+	byCheck := GroupClusterlintDiagnosticsByCheck(diags)
+	checks := make([]string, 0, len(byCheck))
+	for check := range byCheck {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+
+	suite := &clusterlintJUnitSuite{Name: suiteName, Tests: len(checks)}
+	for _, check := range checks {
+		tc := &clusterlintJUnitTestCase{Name: check, ClassName: suiteName}
+
+		var (
+			failures, other []string
+			message         string
+			worst           ClusterlintSeverity
+		)
+		for _, d := range byCheck[check] {
+			line := clusterlintDiagnosticLine(d)
+			severity := ClusterlintSeverity(d.Severity)
+			if !severity.AtLeast(ClusterlintSeverityWarning) {
+				other = append(other, line)
+				continue
+			}
+			if len(failures) == 0 {
+				message = d.Message
+			}
+			if severity.level() > worst.level() {
+				worst = severity
+			}
+			failures = append(failures, line)
+		}
+		if len(failures) > 0 {
+			suite.Failures++
+			tc.Failure = &clusterlintJUnitFailure{
+				Message: message,
+				Type:    string(worst),
+				Text:    strings.Join(failures, "\n"),
+			}
+		}
+		tc.SystemOut = strings.Join(other, "\n")
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// clusterlintDiagnosticLine formats a diagnostic as a line of a JUnit report,
+// e.g. "[warning] default/Pod/web-1: message".
+func clusterlintDiagnosticLine(d *ClusterlintDiagnostic) string {
+	return "[" + d.Severity + "] " + clusterlintObjectKey(d.Object) + ": " + d.Message
+}
@@ -0,0 +1,189 @@
+package godo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterlintSeverity_AtLeast(t *testing.T) {
+	tests := []struct {
+		s, min ClusterlintSeverity
+		want   bool
+	}{
+		{ClusterlintSeverityError, ClusterlintSeverityError, true},
+		{ClusterlintSeverityError, ClusterlintSeverityWarning, true},
+		{ClusterlintSeverityError, ClusterlintSeveritySuggestion, true},
+		{ClusterlintSeverityWarning, ClusterlintSeverityError, false},
+		{ClusterlintSeverityWarning, ClusterlintSeverityWarning, true},
+		{ClusterlintSeveritySuggestion, ClusterlintSeverityWarning, false},
+		{ClusterlintSeverity("info"), ClusterlintSeveritySuggestion, false},
+		{ClusterlintSeveritySuggestion, ClusterlintSeverity("info"), true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.s.AtLeast(tt.min), "%s at least %s", tt.s, tt.min)
+	}
+}
+
+func TestFilterClusterlintDiagnostics(t *testing.T) {
+	diags := []*ClusterlintDiagnostic{
+		{CheckName: "bare-pods", Severity: "error"},
+		{CheckName: "unused-config-map", Severity: "warning"},
+		{CheckName: "resource-requirements", Severity: "suggestion"},
+		{CheckName: "privileged-containers", Severity: "error"},
+	}
+
+	got := FilterClusterlintDiagnostics(diags, ClusterlintSeverityError)
+	assert.Equal(t, []*ClusterlintDiagnostic{diags[0], diags[3]}, got)
+
+	got = FilterClusterlintDiagnostics(diags, ClusterlintSeverityWarning)
+	assert.Equal(t, []*ClusterlintDiagnostic{diags[0], diags[1], diags[3]}, got)
+
+	got = FilterClusterlintDiagnostics(diags, ClusterlintSeveritySuggestion)
+	assert.Equal(t, diags, got)
+
+	assert.Empty(t, FilterClusterlintDiagnostics(nil, ClusterlintSeverityError))
+}
+
+func TestGroupClusterlintDiagnosticsByOwner(t *testing.T) {
+	web := &ClusterlintDiagnostic{
+		CheckName: "resource-requirements",
+		Object: &ClusterlintObject{Kind: "Pod", Name: "web-1", Owners: []*ClusterlintOwner{
+			{Kind: "ReplicaSet", Name: "web-5d4f8"},
+		}},
+	}
+	web2 := &ClusterlintDiagnostic{
+		CheckName: "latest-tag",
+		Object: &ClusterlintObject{Kind: "Pod", Name: "web-2", Owners: []*ClusterlintOwner{
+			{Kind: "ReplicaSet", Name: "web-5d4f8"},
+		}},
+	}
+	agent := &ClusterlintDiagnostic{
+		CheckName: "privileged-containers",
+		Object: &ClusterlintObject{Kind: "Pod", Name: "agent-x", Owners: []*ClusterlintOwner{
+			{Kind: "DaemonSet", Name: "agent"},
+			{Kind: "Node", Name: "pool-a-1"},
+		}},
+	}
+	bare := &ClusterlintDiagnostic{
+		CheckName: "bare-pods",
+		Object:    &ClusterlintObject{Kind: "Pod", Name: "debug"},
+	}
+	noObject := &ClusterlintDiagnostic{CheckName: "node-name-pod-selector"}
+
+	got := GroupClusterlintDiagnosticsByOwner([]*ClusterlintDiagnostic{web, agent, bare, web2, noObject})
+	assert.Equal(t, map[string][]*ClusterlintDiagnostic{
+		"ReplicaSet/web-5d4f8": {web, web2},
+		"DaemonSet/agent":      {agent},
+		"Node/pool-a-1":        {agent},
+		ClusterlintUnowned:     {bare, noObject},
+	}, got)
+
+	assert.Empty(t, GroupClusterlintDiagnosticsByOwner(nil))
+}
+
+func TestGroupClusterlintDiagnosticsByObject(t *testing.T) {
+	webRequirements := &ClusterlintDiagnostic{
+		CheckName: "resource-requirements",
+		Object:    &ClusterlintObject{Kind: "Pod", Name: "web-1", Namespace: "default"},
+	}
+	webTag := &ClusterlintDiagnostic{
+		CheckName: "latest-tag",
+		Object:    &ClusterlintObject{Kind: "Pod", Name: "web-1", Namespace: "default"},
+	}
+	otherNamespace := &ClusterlintDiagnostic{
+		CheckName: "latest-tag",
+		Object:    &ClusterlintObject{Kind: "Pod", Name: "web-1", Namespace: "staging"},
+	}
+	node := &ClusterlintDiagnostic{
+		CheckName: "node-labels-and-taints",
+		Object:    &ClusterlintObject{Kind: "Node", Name: "pool-a-1"},
+	}
+	noObject := &ClusterlintDiagnostic{CheckName: "admission-controller-webhook"}
+
+	got := GroupClusterlintDiagnosticsByObject([]*ClusterlintDiagnostic{webRequirements, otherNamespace, node, webTag, noObject})
+	assert.Equal(t, map[string][]*ClusterlintDiagnostic{
+		"default/Pod/web-1": {webRequirements, webTag},
+		"staging/Pod/web-1": {otherNamespace},
+		"/Node/pool-a-1":    {node},
+		ClusterlintNoObject: {noObject},
+	}, got)
+
+	assert.Empty(t, GroupClusterlintDiagnosticsByObject(nil))
+}
+
+func TestGroupClusterlintDiagnosticsByCheck(t *testing.T) {
+	web := &ClusterlintDiagnostic{CheckName: "latest-tag", Object: &ClusterlintObject{Kind: "Pod", Name: "web-1"}}
+	api := &ClusterlintDiagnostic{CheckName: "latest-tag", Object: &ClusterlintObject{Kind: "Pod", Name: "api-1"}}
+	bare := &ClusterlintDiagnostic{CheckName: "bare-pods"}
+
+	got := GroupClusterlintDiagnosticsByCheck([]*ClusterlintDiagnostic{web, bare, api})
+	assert.Equal(t, map[string][]*ClusterlintDiagnostic{
+		"latest-tag": {web, api},
+		"bare-pods":  {bare},
+	}, got)
+}
+
+func TestKubernetesClusters_GetClusterlintCatalog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	got, resp, err := kubeSvc.GetClusterlintCatalog(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, got.Groups, "doks")
+	assert.Contains(t, got.Checks, "latest-tag")
+	assert.Contains(t, got.Checks, "unused-secret")
+	assert.Contains(t, got.Checks, "cronjob-concurrency")
+
+	// Changing the returned catalog does not change the known one.
+	got.Checks[0] = "changed"
+	got.Groups = append(got.Groups, "custom")
+	assert.Equal(t, KnownClusterlintCatalog(), knownClusterlintCatalog)
+	assert.NotContains(t, KnownClusterlintCatalog().Checks, "changed")
+}
+
+func TestClusterlintDiagnosticsToJUnit(t *testing.T) {
+	diags := []*ClusterlintDiagnostic{
+		{
+			CheckName: "latest-tag",
+			Severity:  "warning",
+			Message:   "Avoid using latest tag for container 'web' in pod 'web-1'",
+			Object:    &ClusterlintObject{Kind: "Pod", Name: "web-1", Namespace: "default"},
+		},
+		{
+			CheckName: "privileged-containers",
+			Severity:  "error",
+			Message:   "Privileged container 'agent' found. Please ensure that the image is from a trusted source.",
+			Object:    &ClusterlintObject{Kind: "Pod", Name: "agent-x", Namespace: "kube-system"},
+		},
+		{
+			CheckName: "latest-tag",
+			Severity:  "error",
+			Message:   "Avoid using latest tag for container 'api' in pod 'api-1'",
+			Object:    &ClusterlintObject{Kind: "Pod", Name: "api-1", Namespace: "default"},
+		},
+		{
+			CheckName: "unused-config-map",
+			Severity:  "suggestion",
+			Message:   "Unused config map",
+			Object:    &ClusterlintObject{Kind: "ConfigMap", Name: "old-settings", Namespace: "default"},
+		},
+		{
+			CheckName: "admission-controller-webhook",
+			Severity:  "warning",
+			Message:   "Validating webhook <is-valid> with a service ref & no namespace",
+		},
+	}
+
+	got, err := ClusterlintDiagnosticsToJUnit(diags, "clusterlint")
+	require.NoError(t, err)
+
+	want, err := os.ReadFile("testdata/clusterlint_junit.xml")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
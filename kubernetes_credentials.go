@@ -0,0 +1,75 @@
+package godo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultKubernetesCredentialRefreshThreshold is how long before their
+// expiry a KubernetesCredentialSource refreshes credentials by default.
+const DefaultKubernetesCredentialRefreshThreshold = time.Minute
+
+// IsExpired reports whether the credentials have expired at now. Credentials
+// without an expiry are considered expired.
+func (c *KubernetesClusterCredentials) IsExpired(now time.Time) bool {
+	return c.ExpiresWithin(0, now)
+}
+
+// ExpiresWithin reports whether the credentials expire within d of now, or
+// have already expired.
+func (c *KubernetesClusterCredentials) ExpiresWithin(d time.Duration, now time.Time) bool {
+	return !now.Add(d).Before(c.ExpiresAt)
+}
+
+// KubernetesCredentialSource hands out API server credentials for a cluster.
+// The last credentials are cached and only refetched once they are within
+// RefreshThreshold of their expiry. It is safe for concurrent use.
+type KubernetesCredentialSource struct {
+	// RefreshThreshold is how long before their expiry credentials are
+	// refreshed. It must not be changed once Token has been called.
+	RefreshThreshold time.Duration
+
+	svc           KubernetesService
+	clusterID     string
+	expirySeconds int
+	now           func() time.Time
+
+	mu    sync.Mutex
+	creds *KubernetesClusterCredentials
+}
+
+// NewKubernetesCredentialSource returns a KubernetesCredentialSource that
+// fetches credentials for the cluster valid for expirySeconds. If
+// expirySeconds is 0, the API default is used.
+func NewKubernetesCredentialSource(svc KubernetesService, clusterID string, expirySeconds int) *KubernetesCredentialSource {
+	return &KubernetesCredentialSource{
+		RefreshThreshold: DefaultKubernetesCredentialRefreshThreshold,
+		svc:              svc,
+		clusterID:        clusterID,
+		expirySeconds:    expirySeconds,
+		now:              time.Now,
+	}
+}
+
+// Token returns valid credentials for the cluster, fetching new ones if the
+// cached credentials are missing or about to expire.
+func (s *KubernetesCredentialSource) Token(ctx context.Context) (*KubernetesClusterCredentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.creds != nil && !s.creds.ExpiresWithin(s.RefreshThreshold, s.now()) {
+		return s.creds, nil
+	}
+
+	req := &KubernetesClusterCredentialsGetRequest{}
+	if s.expirySeconds > 0 {
+		req.ExpirySeconds = PtrTo(s.expirySeconds)
+	}
+	creds, _, err := s.svc.GetCredentials(ctx, s.clusterID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.creds = creds
+	return creds, nil
+}
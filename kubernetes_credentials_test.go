@@ -0,0 +1,97 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClusterCredentials_Expiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		expiresAt    time.Time
+		wantExpired  bool
+		wantWithin5m bool
+	}{
+		{name: "expired", expiresAt: now.Add(-time.Minute), wantExpired: true, wantWithin5m: true},
+		{name: "expires now", expiresAt: now, wantExpired: true, wantWithin5m: true},
+		{name: "about to expire", expiresAt: now.Add(2 * time.Minute), wantWithin5m: true},
+		{name: "valid", expiresAt: now.Add(time.Hour)},
+		{name: "no expiry", expiresAt: time.Time{}, wantExpired: true, wantWithin5m: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &KubernetesClusterCredentials{ExpiresAt: tt.expiresAt}
+			assert.Equal(t, tt.wantExpired, c.IsExpired(now))
+			assert.Equal(t, tt.wantWithin5m, c.ExpiresWithin(5*time.Minute, now))
+		})
+	}
+}
+
+func TestKubernetesCredentialSource_Token(t *testing.T) {
+	setup()
+	defer teardown()
+
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	fetches := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/credentials", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "expiry_seconds=3600", r.URL.RawQuery)
+		fetches++
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, fetches, now.Add(time.Hour).Format(time.RFC3339))
+	})
+
+	src := NewKubernetesCredentialSource(client.Kubernetes, "deadbeef-dead-4aa5-beef-deadbeef347d", 3600)
+	src.now = func() time.Time { return now }
+
+	creds, err := src.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", creds.Token)
+
+	// Cached while still valid.
+	now = now.Add(30 * time.Minute)
+	creds, err = src.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", creds.Token)
+	assert.Equal(t, 1, fetches)
+
+	// Refreshed within the threshold of the expiry.
+	now = now.Add(29*time.Minute + 30*time.Second)
+	creds, err = src.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", creds.Token)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestKubernetesCredentialSource_TokenExpired(t *testing.T) {
+	setup()
+	defer teardown()
+
+	fetches := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/credentials", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.URL.RawQuery)
+		fetches++
+		// The API hands out credentials that are already expired.
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": "2020-01-01T00:00:00Z"}`, fetches)
+	})
+
+	src := NewKubernetesCredentialSource(client.Kubernetes, "deadbeef-dead-4aa5-beef-deadbeef347d", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := src.Token(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 5, fetches)
+}
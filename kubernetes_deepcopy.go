@@ -0,0 +1,129 @@
+package godo
+
+// DeepCopy returns a copy of the cluster that shares no maps, slices or
+// pointers with the original, so that either can be modified without
+// affecting the other. It returns nil for a nil cluster.
+func (kc *KubernetesCluster) DeepCopy() *KubernetesCluster {
+	if kc == nil {
+		return nil
+	}
+	out := *kc
+	out.Tags = copyStrings(kc.Tags)
+	if kc.NodePools != nil {
+		out.NodePools = make([]*KubernetesNodePool, len(kc.NodePools))
+		for i, pool := range kc.NodePools {
+			out.NodePools[i] = pool.DeepCopy()
+		}
+	}
+	out.MaintenancePolicy = kc.MaintenancePolicy.DeepCopy()
+	out.ControlPlaneFirewall = kc.ControlPlaneFirewall.DeepCopy()
+	out.ClusterAutoscalerConfiguration = kc.ClusterAutoscalerConfiguration.DeepCopy()
+	out.Status = kc.Status.DeepCopy()
+	return &out
+}
+
+// DeepCopy returns a copy of the node pool, including its nodes, that shares
+// no maps, slices or pointers with the original. It returns nil for a nil node
+// pool.
+func (p *KubernetesNodePool) DeepCopy() *KubernetesNodePool {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.Tags = copyStrings(p.Tags)
+	if p.Labels != nil {
+		out.Labels = make(map[string]string, len(p.Labels))
+		for k, v := range p.Labels {
+			out.Labels[k] = v
+		}
+	}
+	if p.Taints != nil {
+		out.Taints = append([]Taint{}, p.Taints...)
+	}
+	if p.Nodes != nil {
+		out.Nodes = make([]*KubernetesNode, len(p.Nodes))
+		for i, node := range p.Nodes {
+			out.Nodes[i] = node.DeepCopy()
+		}
+	}
+	return &out
+}
+
+// DeepCopy returns a copy of the node that shares no pointers with the
+// original. It returns nil for a nil node.
+func (n *KubernetesNode) DeepCopy() *KubernetesNode {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Status = n.Status.DeepCopy()
+	return &out
+}
+
+// DeepCopy returns a copy of the node status. It returns nil for a nil status.
+func (s *KubernetesNodeStatus) DeepCopy() *KubernetesNodeStatus {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	return &out
+}
+
+// DeepCopy returns a copy of the cluster status. It returns nil for a nil
+// status.
+func (s *KubernetesClusterStatus) DeepCopy() *KubernetesClusterStatus {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	return &out
+}
+
+// DeepCopy returns a copy of the maintenance policy. It returns nil for a nil
+// policy.
+func (p *KubernetesMaintenancePolicy) DeepCopy() *KubernetesMaintenancePolicy {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	return &out
+}
+
+// DeepCopy returns a copy of the control plane firewall that shares no slices
+// or pointers with the original. It returns nil for a nil firewall.
+func (f *KubernetesControlPlaneFirewall) DeepCopy() *KubernetesControlPlaneFirewall {
+	if f == nil {
+		return nil
+	}
+	out := *f
+	if f.Enabled != nil {
+		out.Enabled = PtrTo(*f.Enabled)
+	}
+	out.AllowedAddresses = copyStrings(f.AllowedAddresses)
+	return &out
+}
+
+// DeepCopy returns a copy of the autoscaler configuration that shares no
+// pointers with the original. It returns nil for a nil configuration.
+func (c *KubernetesClusterAutoscalerConfiguration) DeepCopy() *KubernetesClusterAutoscalerConfiguration {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	if c.ScaleDownUtilizationThreshold != nil {
+		out.ScaleDownUtilizationThreshold = PtrTo(*c.ScaleDownUtilizationThreshold)
+	}
+	if c.ScaleDownUnneededTime != nil {
+		out.ScaleDownUnneededTime = PtrTo(*c.ScaleDownUnneededTime)
+	}
+	return &out
+}
+
+// copyStrings returns a copy of s, preserving the difference between a nil and
+// an empty slice.
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
@@ -0,0 +1,79 @@
+package godo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeepCopyCluster() *KubernetesCluster {
+	return &KubernetesCluster{
+		ID:   "deadbeef-dead-4aa5-beef-deadbeef347d",
+		Name: "prod",
+		Tags: []string{"k8s", "env:prod"},
+		NodePools: []*KubernetesNodePool{
+			{
+				ID:     "pool-1",
+				Name:   "workers",
+				Tags:   []string{"workers"},
+				Labels: map[string]string{"priority": "high"},
+				Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}},
+				Nodes: []*KubernetesNode{
+					{ID: "node-1", Status: &KubernetesNodeStatus{State: "running"}},
+				},
+			},
+		},
+		MaintenancePolicy: &KubernetesMaintenancePolicy{StartTime: "00:00", Day: KubernetesMaintenanceDayMonday},
+		ControlPlaneFirewall: &KubernetesControlPlaneFirewall{
+			Enabled:          PtrTo(true),
+			AllowedAddresses: []string{"1.2.3.4/32"},
+		},
+		ClusterAutoscalerConfiguration: &KubernetesClusterAutoscalerConfiguration{
+			ScaleDownUtilizationThreshold: PtrTo(0.5),
+			ScaleDownUnneededTime:         PtrTo("1m"),
+		},
+		Status: &KubernetesClusterStatus{State: KubernetesClusterStatusRunning},
+	}
+}
+
+func TestKubernetesCluster_DeepCopy(t *testing.T) {
+	original := testDeepCopyCluster()
+	cp := original.DeepCopy()
+	require.Equal(t, original, cp)
+
+	cp.Tags[1] = "env:staging"
+	cp.NodePools[0].Tags[0] = "changed"
+	cp.NodePools[0].Labels["priority"] = "low"
+	cp.NodePools[0].Taints[0].Effect = TaintEffectNoExecute
+	cp.NodePools[0].Nodes[0].Status.State = "deleting"
+	cp.NodePools = append(cp.NodePools, &KubernetesNodePool{ID: "pool-2"})
+	cp.MaintenancePolicy.StartTime = "12:00"
+	*cp.ControlPlaneFirewall.Enabled = false
+	cp.ControlPlaneFirewall.AllowedAddresses[0] = "0.0.0.0/0"
+	*cp.ClusterAutoscalerConfiguration.ScaleDownUtilizationThreshold = 0.9
+	*cp.ClusterAutoscalerConfiguration.ScaleDownUnneededTime = "10m"
+	cp.Status.State = KubernetesClusterStatusError
+
+	assert.Equal(t, testDeepCopyCluster(), original)
+}
+
+func TestKubernetesNodePool_DeepCopy(t *testing.T) {
+	original := testDeepCopyCluster().NodePools[0]
+	cp := original.DeepCopy()
+	require.Equal(t, original, cp)
+
+	cp.Labels["service"] = "backend"
+	cp.Taints = append(cp.Taints[:0], Taint{Key: "other"})
+	cp.Nodes[0].ID = "node-2"
+
+	assert.Equal(t, testDeepCopyCluster().NodePools[0], original)
+}
+
+func TestKubernetesCluster_DeepCopy_Nil(t *testing.T) {
+	var kc *KubernetesCluster
+	assert.Nil(t, kc.DeepCopy())
+
+	cp := (&KubernetesCluster{ID: "deadbeef-dead-4aa5-beef-deadbeef347d"}).DeepCopy()
+	assert.Equal(t, &KubernetesCluster{ID: "deadbeef-dead-4aa5-beef-deadbeef347d"}, cp)
+}
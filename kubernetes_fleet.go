@@ -0,0 +1,77 @@
+package godo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// fleetClock is how runFleet tells the time and waits for the rate limit to
+// reset. It is replaced in tests.
+type fleetClock struct {
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// defaultFleetClock uses the wall clock.
+var defaultFleetClock = fleetClock{now: time.Now, sleep: sleepContext}
+
+// runFleet calls fn for each of items, running up to concurrency calls at
+// once; values below 1 run one call at a time. When a call returns a response
+// reporting that the API rate limit is exhausted, no further calls are started
+// until the limit resets, using clock to wait. The returned errors are in the
+// order of items, with nil for the calls that succeeded. If ctx is done before
+// all calls started, the items that were not processed get the context's
+// error.
+func runFleet[T any](ctx context.Context, clock fleetClock, items []T, concurrency int, fn func(context.Context, T) (*Response, error)) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		pauseUntil time.Time
+		errs       = make([]error, len(items))
+		sem        = make(chan struct{}, concurrency)
+	)
+	for i, item := range items {
+		sem <- struct{}{}
+
+		mu.Lock()
+		pause := pauseUntil.Sub(clock.now())
+		mu.Unlock()
+		err := ctx.Err()
+		if err == nil && pause > 0 {
+			err = clock.sleep(ctx, pause)
+		}
+		if err != nil {
+			<-sem
+			for j := i; j < len(items); j++ {
+				errs[j] = err
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			resp, err := fn(ctx, item)
+			errs[i] = err
+			if resp != nil && resp.Rate.Remaining == 0 && resp.Rate.Reset.After(clock.now()) {
+				mu.Lock()
+				if resp.Rate.Reset.After(pauseUntil) {
+					pauseUntil = resp.Rate.Reset.Time
+				}
+				mu.Unlock()
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	return errs
+}
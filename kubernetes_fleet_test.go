@@ -0,0 +1,124 @@
+package godo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFleet(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+	)
+	errs := runFleet(context.Background(), defaultFleetClock, []int{1, 2, 3, 4, 5, 6}, 2, func(ctx context.Context, n int) (*Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if n == 4 {
+			return nil, errors.New("boom")
+		}
+		return &Response{}, nil
+	})
+	require.Len(t, errs, 6)
+	assert.EqualError(t, errs[3], "boom")
+	for i, err := range errs {
+		if i != 3 {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, 2, maxSeen)
+}
+
+// fakeFleetClock is a fleetClock whose time only moves when it sleeps.
+type fakeFleetClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeFleetClock) clock() fleetClock {
+	return fleetClock{
+		now: func() time.Time {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.now
+		},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.sleeps = append(c.sleeps, d)
+			c.now = c.now.Add(d)
+			return ctx.Err()
+		},
+	}
+}
+
+func TestRunFleet_RateLimitExhausted(t *testing.T) {
+	fake := &fakeFleetClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	clock := fake.clock()
+	reset := fake.now.Add(time.Minute)
+
+	var (
+		mu     sync.Mutex
+		starts = make(map[int]time.Time)
+	)
+	errs := runFleet(context.Background(), clock, []int{1, 2, 3}, 1, func(ctx context.Context, n int) (*Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		starts[n] = clock.now()
+		if n != 1 {
+			return &Response{Rate: Rate{Limit: 5000, Remaining: 4999}}, nil
+		}
+		// The first call uses up the rate limit.
+		return &Response{Rate: Rate{Limit: 5000, Remaining: 0, Reset: Timestamp{reset}}}, nil
+	})
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []time.Duration{time.Minute}, fake.sleeps)
+	require.Len(t, starts, 3)
+	assert.Equal(t, reset, starts[2])
+	assert.Equal(t, reset, starts[3])
+}
+
+func TestRunFleet_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := &fakeFleetClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	clock := fake.clock()
+	// The context is canceled while pausing for the rate limit to reset.
+	sleep := clock.sleep
+	clock.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleep(ctx, d)
+	}
+
+	calls := 0
+	errs := runFleet(ctx, clock, []int{1, 2, 3}, 1, func(ctx context.Context, n int) (*Response, error) {
+		calls++
+		// Exhaust the rate limit for an hour.
+		return &Response{Rate: Rate{Remaining: 0, Reset: Timestamp{clock.now().Add(time.Hour)}}}, nil
+	})
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []time.Duration{time.Hour}, fake.sleeps)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], context.Canceled)
+	assert.ErrorIs(t, errs[2], context.Canceled)
+}
@@ -0,0 +1,84 @@
+package godo
+
+import "context"
+
+// KubernetesClusterIterator iterates over the clusters of an account, fetching
+// one page at a time as the caller advances, so that only a single page is
+// held in memory. Create one with ClustersIterator:
+//
+//	it := client.Kubernetes.ClustersIterator(ctx, nil)
+//	for it.Next() {
+//		cluster := it.Cluster()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type KubernetesClusterIterator struct {
+	svc  *KubernetesServiceOp
+	ctx  context.Context
+	opts ListOptions
+
+	page    []*KubernetesCluster
+	cluster *KubernetesCluster
+	done    bool
+	err     error
+}
+
+// ClustersIterator returns an iterator over all clusters, starting at the page
+// given in opts. Pages hold DefaultKubernetesListAllPerPage clusters unless
+// opts sets PerPage. No request is made until Next is called.
+func (svc *KubernetesServiceOp) ClustersIterator(ctx context.Context, opts *ListOptions) *KubernetesClusterIterator {
+	return &KubernetesClusterIterator{
+		svc:  svc,
+		ctx:  ctx,
+		opts: listAllOptions(opts),
+	}
+}
+
+// Next advances the iterator to the next cluster, fetching the next page if
+// needed. It returns false when there are no more clusters or a page could
+// not be fetched; Err tells the two apart.
+func (it *KubernetesClusterIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done {
+			it.cluster = nil
+			return false
+		}
+		it.fetch()
+	}
+	it.cluster, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// fetch loads the next page of clusters.
+func (it *KubernetesClusterIterator) fetch() {
+	clusters, resp, err := it.svc.List(it.ctx, &it.opts)
+	if err != nil {
+		it.done, it.err = true, err
+		return
+	}
+	it.page = clusters
+
+	if resp.Links == nil || resp.Links.IsLastPage() {
+		it.done = true
+		return
+	}
+	page, err := resp.Links.CurrentPage()
+	if err != nil {
+		it.done, it.err = true, err
+		return
+	}
+	it.opts.Page = page + 1
+}
+
+// Cluster returns the cluster the iterator is at. It returns nil before the
+// first call to Next and after Next returned false.
+func (it *KubernetesClusterIterator) Cluster() *KubernetesCluster {
+	return it.cluster
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *KubernetesClusterIterator) Err() error {
+	return it.err
+}
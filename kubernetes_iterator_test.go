@@ -0,0 +1,91 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClusters_ClustersIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var pages []string
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		switch page {
+		case "", "1":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [{"id": "cluster-1"}, {"id": "cluster-2"}],
+				"links": {"pages": {"next": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2", "last": "https://api.digitalocean.com/v2/kubernetes/clusters?page=3"}}
+			}`)
+		case "2":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [{"id": "cluster-3"}, {"id": "cluster-4"}],
+				"links": {"pages": {"prev": "https://api.digitalocean.com/v2/kubernetes/clusters?page=1", "next": "https://api.digitalocean.com/v2/kubernetes/clusters?page=3", "last": "https://api.digitalocean.com/v2/kubernetes/clusters?page=3"}}
+			}`)
+		case "3":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [{"id": "cluster-5"}],
+				"links": {"pages": {"first": "https://api.digitalocean.com/v2/kubernetes/clusters?page=1", "prev": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2"}}
+			}`)
+		default:
+			t.Fatalf("unexpected page %q", page)
+		}
+	})
+
+	it := kubeSvc.ClustersIterator(ctx, nil)
+	assert.Nil(t, it.Cluster())
+	assert.Empty(t, pages, "no request before Next")
+
+	seen := make(map[string]int)
+	var order []string
+	for it.Next() {
+		id := it.Cluster().ID
+		seen[id]++
+		order = append(order, id)
+		if id == "cluster-2" {
+			assert.Len(t, pages, 1, "pages are fetched lazily")
+		}
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"cluster-1", "cluster-2", "cluster-3", "cluster-4", "cluster-5"}, order)
+	for id, n := range seen {
+		assert.Equal(t, 1, n, "cluster %s visited %d times", id, n)
+	}
+	assert.Equal(t, []string{"", "2", "3"}, pages)
+	assert.Nil(t, it.Cluster())
+	assert.False(t, it.Next())
+}
+
+func TestKubernetesClusters_ClustersIterator_Error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{
+			"kubernetes_clusters": [{"id": "cluster-1"}],
+			"links": {"pages": {"next": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2", "last": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2"}}
+		}`)
+	})
+
+	it := kubeSvc.ClustersIterator(ctx, nil)
+	require.True(t, it.Next())
+	assert.Equal(t, "cluster-1", it.Cluster().ID)
+	assert.False(t, it.Next())
+	require.Error(t, it.Err())
+}
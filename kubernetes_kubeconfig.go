@@ -0,0 +1,488 @@
+package godo
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfig is a minimal model of a kubeconfig file. Fields that are not
+// modeled explicitly are kept in the Extra maps so that a parsed file can be
+// written back without losing information.
+type kubeconfig struct {
+	APIVersion     string                 `yaml:"apiVersion,omitempty"`
+	Kind           string                 `yaml:"kind,omitempty"`
+	Clusters       []*kubeconfigCluster   `yaml:"clusters"`
+	Contexts       []*kubeconfigContext   `yaml:"contexts"`
+	CurrentContext string                 `yaml:"current-context"`
+	Users          []*kubeconfigUser      `yaml:"users"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+type kubeconfigCluster struct {
+	Name    string                `yaml:"name"`
+	Cluster kubeconfigClusterInfo `yaml:"cluster"`
+}
+
+type kubeconfigClusterInfo struct {
+	Server                   string                 `yaml:"server"`
+	CertificateAuthorityData string                 `yaml:"certificate-authority-data,omitempty"`
+	Extra                    map[string]interface{} `yaml:",inline"`
+}
+
+type kubeconfigContext struct {
+	Name    string                `yaml:"name"`
+	Context kubeconfigContextInfo `yaml:"context"`
+}
+
+type kubeconfigContextInfo struct {
+	Cluster   string                 `yaml:"cluster"`
+	User      string                 `yaml:"user"`
+	Namespace string                 `yaml:"namespace,omitempty"`
+	Extra     map[string]interface{} `yaml:",inline"`
+}
+
+type kubeconfigUser struct {
+	Name string             `yaml:"name"`
+	User kubeconfigUserInfo `yaml:"user"`
+}
+
+type kubeconfigUserInfo struct {
+	Token                 string                 `yaml:"token,omitempty"`
+	ClientCertificateData string                 `yaml:"client-certificate-data,omitempty"`
+	ClientKeyData         string                 `yaml:"client-key-data,omitempty"`
+	Exec                  *kubeconfigExec        `yaml:"exec,omitempty"`
+	Extra                 map[string]interface{} `yaml:",inline"`
+}
+
+type kubeconfigExec struct {
+	APIVersion string                 `yaml:"apiVersion,omitempty"`
+	Command    string                 `yaml:"command"`
+	Args       []string               `yaml:"args,omitempty"`
+	Env        []*kubeconfigExecEnv   `yaml:"env,omitempty"`
+	Extra      map[string]interface{} `yaml:",inline"`
+}
+
+type kubeconfigExecEnv struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+func parseKubeconfig(data []byte) (*kubeconfig, error) {
+	kc := new(kubeconfig)
+	if err := yaml.Unmarshal(data, kc); err != nil {
+		return nil, err
+	}
+	return kc, nil
+}
+
+// marshal encodes the kubeconfig as YAML with the indentation kubectl uses.
+func (kc *kubeconfig) marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(kc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// context returns the context with the given name, or nil.
+func (kc *kubeconfig) context(name string) *kubeconfigContext {
+	for _, c := range kc.Contexts {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// user returns the user with the given name, or nil.
+func (kc *kubeconfig) user(name string) *kubeconfigUser {
+	for _, u := range kc.Users {
+		if u.Name == name {
+			return u
+		}
+	}
+	return nil
+}
+
+// currentUser returns the user of the current context. If there is no current
+// context, the only user of the file is returned, if any.
+func (kc *kubeconfig) currentUser() *kubeconfigUser {
+	if c := kc.context(kc.CurrentContext); c != nil {
+		return kc.user(c.Context.User)
+	}
+	if len(kc.Users) == 1 {
+		return kc.Users[0]
+	}
+	return nil
+}
+
+// renameCurrentContext renames the current context, and the cluster and user
+// it refers to, to name. If there is no current context, the only context of
+// the file is renamed and made current.
+func (kc *kubeconfig) renameCurrentContext(name string) error {
+	current := kc.context(kc.CurrentContext)
+	if current == nil && len(kc.Contexts) == 1 {
+		current = kc.Contexts[0]
+	}
+	if current == nil {
+		return errors.New("kubeconfig has no current context")
+	}
+
+	oldCluster, oldUser := current.Context.Cluster, current.Context.User
+	for _, c := range kc.Clusters {
+		if c.Name == oldCluster {
+			c.Name = name
+		}
+	}
+	for _, u := range kc.Users {
+		if u.Name == oldUser {
+			u.Name = name
+		}
+	}
+	for _, c := range kc.Contexts {
+		if c.Context.Cluster == oldCluster {
+			c.Context.Cluster = name
+		}
+		if c.Context.User == oldUser {
+			c.Context.User = name
+		}
+	}
+	current.Name = name
+	kc.CurrentContext = name
+	return nil
+}
+
+// KubeconfigFile is a parsed kubeconfig file. It only models the fields that
+// are commonly needed to connect to a cluster.
+type KubeconfigFile struct {
+	Clusters           []*KubeconfigCluster
+	Contexts           []*KubeconfigContext
+	Users              []*KubeconfigUser
+	CurrentContextName string
+}
+
+// KubeconfigCluster is a cluster entry of a kubeconfig.
+type KubeconfigCluster struct {
+	Name   string
+	Server string
+	// CertificateAuthorityData is the PEM encoded CA certificate of the
+	// cluster.
+	CertificateAuthorityData []byte
+}
+
+// KubeconfigContext is a context entry of a kubeconfig.
+type KubeconfigContext struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+}
+
+// CurrentContext returns the current context, or nil if it is not set or
+// does not exist.
+func (f *KubeconfigFile) CurrentContext() *KubeconfigContext {
+	for _, c := range f.Contexts {
+		if c.Name == f.CurrentContextName {
+			return c
+		}
+	}
+	return nil
+}
+
+// Cluster returns the cluster with the given name, or nil.
+func (f *KubeconfigFile) Cluster(name string) *KubeconfigCluster {
+	for _, c := range f.Clusters {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// ServerURL returns the API server URL of the current context's cluster. If
+// there is no current context, the server of the only cluster of the file is
+// returned. It returns an empty string if the server cannot be determined.
+func (f *KubeconfigFile) ServerURL() string {
+	var cluster *KubeconfigCluster
+	if c := f.CurrentContext(); c != nil {
+		cluster = f.Cluster(c.Cluster)
+	} else if len(f.Clusters) == 1 {
+		cluster = f.Clusters[0]
+	}
+	if cluster == nil {
+		return ""
+	}
+	return cluster.Server
+}
+
+// KubeconfigUser is a user entry of a kubeconfig. Users that obtain their
+// credentials through an exec plugin, such as doctl, have the plugin's
+// command, arguments and environment set in the Exec fields.
+type KubeconfigUser struct {
+	Name        string
+	ExecCommand string
+	ExecArgs    []string
+	ExecEnv     map[string]string
+}
+
+// UsesExec reports whether the user obtains its credentials through an exec
+// plugin.
+func (u *KubeconfigUser) UsesExec() bool {
+	return u.ExecCommand != ""
+}
+
+func (u *kubeconfigUser) toKubeconfigUser() *KubeconfigUser {
+	user := &KubeconfigUser{Name: u.Name}
+	if exec := u.User.Exec; exec != nil {
+		user.ExecCommand = exec.Command
+		user.ExecArgs = exec.Args
+		if len(exec.Env) > 0 {
+			user.ExecEnv = make(map[string]string, len(exec.Env))
+			for _, env := range exec.Env {
+				user.ExecEnv[env.Name] = env.Value
+			}
+		}
+	}
+	return user
+}
+
+// Parse parses the kubeconfig into a KubeconfigFile.
+func (c *KubernetesClusterConfig) Parse() (*KubeconfigFile, error) {
+	kc, err := parseKubeconfig(c.KubeconfigYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &KubeconfigFile{
+		Clusters:           make([]*KubeconfigCluster, 0, len(kc.Clusters)),
+		Contexts:           make([]*KubeconfigContext, 0, len(kc.Contexts)),
+		Users:              make([]*KubeconfigUser, 0, len(kc.Users)),
+		CurrentContextName: kc.CurrentContext,
+	}
+	for _, cl := range kc.Clusters {
+		cluster := &KubeconfigCluster{Name: cl.Name, Server: cl.Cluster.Server}
+		if data := cl.Cluster.CertificateAuthorityData; data != "" {
+			cluster.CertificateAuthorityData, err = base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, fmt.Errorf("decoding certificate authority data of cluster %q: %w", cl.Name, err)
+			}
+		}
+		f.Clusters = append(f.Clusters, cluster)
+	}
+	for _, ctx := range kc.Contexts {
+		f.Contexts = append(f.Contexts, &KubeconfigContext{
+			Name:      ctx.Name,
+			Cluster:   ctx.Context.Cluster,
+			User:      ctx.Context.User,
+			Namespace: ctx.Context.Namespace,
+		})
+	}
+	for _, u := range kc.Users {
+		f.Users = append(f.Users, u.toKubeconfigUser())
+	}
+	return f, nil
+}
+
+// Users parses the kubeconfig and returns its user entries.
+func (c *KubernetesClusterConfig) Users() ([]*KubeconfigUser, error) {
+	f, err := c.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return f.Users, nil
+}
+
+// CurrentContextName returns the name of the current context of the
+// kubeconfig without parsing the rest of the file. It returns an error if no
+// current context is set.
+func (c *KubernetesClusterConfig) CurrentContextName() (string, error) {
+	var kc struct {
+		CurrentContext string `yaml:"current-context"`
+	}
+	if err := yaml.Unmarshal(c.KubeconfigYAML, &kc); err != nil {
+		return "", err
+	}
+	if kc.CurrentContext == "" {
+		return "", errors.New("kubeconfig has no current context")
+	}
+	return kc.CurrentContext, nil
+}
+
+// kubeconfigSecretUserFields are the user fields, other than those modeled in
+// kubeconfigUserInfo, that Redacted removes as they hold or point to
+// credentials. auth-provider is removed as a whole, as its config holds
+// tokens such as id-token and refresh-token.
+var kubeconfigSecretUserFields = []string{
+	"auth-provider",
+	"client-certificate",
+	"client-key",
+	"password",
+	"token-file",
+}
+
+// Redacted returns a copy of the kubeconfig without the credentials of its
+// users, so that it can be shared or logged: their token, password, client
+// certificate and key, whether embedded or referenced by path, token file,
+// auth provider and the values of the environment variables passed to exec
+// plugins are removed. Clusters, including their CA data, and contexts are
+// kept.
+func (c *KubernetesClusterConfig) Redacted() (*KubernetesClusterConfig, error) {
+	kc, err := parseKubeconfig(c.KubeconfigYAML)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range kc.Users {
+		u.User.Token = ""
+		u.User.ClientCertificateData = ""
+		u.User.ClientKeyData = ""
+		for _, field := range kubeconfigSecretUserFields {
+			delete(u.User.Extra, field)
+		}
+		if u.User.Exec != nil {
+			for _, env := range u.User.Exec.Env {
+				if env != nil {
+					env.Value = ""
+				}
+			}
+		}
+	}
+	data, err := kc.marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &KubernetesClusterConfig{KubeconfigYAML: data}, nil
+}
+
+// KubeconfigMergeOptions configures how a kubeconfig is merged into another.
+type KubeconfigMergeOptions struct {
+	// SetCurrentContext makes the current context of the merged kubeconfig
+	// the current context of the result. Otherwise, the existing current
+	// context is kept, unless there is none.
+	SetCurrentContext bool
+}
+
+// MergeInto merges the clusters, contexts and users of the kubeconfig into
+// existing, keeping the current context of existing. See
+// MergeIntoWithOptions.
+func (c *KubernetesClusterConfig) MergeInto(existing []byte) ([]byte, error) {
+	return c.MergeIntoWithOptions(existing, nil)
+}
+
+// MergeIntoWithOptions merges the clusters, contexts and users of the
+// kubeconfig into existing and returns the result. Entries of existing are
+// replaced by entries of the same name, other entries are left untouched. If
+// existing is empty, the kubeconfig is returned verbatim.
+func (c *KubernetesClusterConfig) MergeIntoWithOptions(existing []byte, opts *KubeconfigMergeOptions) ([]byte, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		return append([]byte(nil), c.KubeconfigYAML...), nil
+	}
+
+	dst, err := parseKubeconfig(existing)
+	if err != nil {
+		return nil, fmt.Errorf("parsing existing kubeconfig: %w", err)
+	}
+	src, err := parseKubeconfig(c.KubeconfigYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range src.Clusters {
+		dst.Clusters = mergeKubeconfigEntry(dst.Clusters, cluster, func(c *kubeconfigCluster) string { return c.Name })
+	}
+	for _, context := range src.Contexts {
+		dst.Contexts = mergeKubeconfigEntry(dst.Contexts, context, func(c *kubeconfigContext) string { return c.Name })
+	}
+	for _, user := range src.Users {
+		dst.Users = mergeKubeconfigEntry(dst.Users, user, func(u *kubeconfigUser) string { return u.Name })
+	}
+	if (opts != nil && opts.SetCurrentContext) || dst.CurrentContext == "" {
+		dst.CurrentContext = src.CurrentContext
+	}
+	return dst.marshal()
+}
+
+// mergeKubeconfigEntry replaces the entry of entries with the same name as
+// entry, or appends entry if there is none.
+func mergeKubeconfigEntry[T any](entries []T, entry T, name func(T) string) []T {
+	for i, e := range entries {
+		if name(e) == name(entry) {
+			entries[i] = entry
+			return entries
+		}
+	}
+	return append(entries, entry)
+}
+
+// TokenExpiry returns when the credentials embedded in the kubeconfig expire.
+// The expiry is read from the "exp" claim of a JWT bearer token or, failing
+// that, from the client certificate. It returns false if the kubeconfig
+// obtains its credentials through an exec plugin, or if no expiry can be
+// determined.
+func (c *KubernetesClusterConfig) TokenExpiry() (time.Time, bool) {
+	kc, err := parseKubeconfig(c.KubeconfigYAML)
+	if err != nil {
+		return time.Time{}, false
+	}
+	user := kc.currentUser()
+	if user == nil || user.User.Exec != nil {
+		return time.Time{}, false
+	}
+	if exp, ok := jwtExpiry(user.User.Token); ok {
+		return exp, true
+	}
+	return certificateExpiry(user.User.ClientCertificateData)
+}
+
+// jwtExpiry returns the expiry of a JWT, if token is one and has an "exp"
+// claim.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0).UTC(), true
+}
+
+// certificateExpiry returns the expiry of a base64 encoded PEM certificate.
+func certificateExpiry(data string) (time.Time, bool) {
+	if data == "" {
+		return time.Time{}, false
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return time.Time{}, false
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter.UTC(), true
+}
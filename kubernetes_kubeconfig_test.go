@@ -0,0 +1,471 @@
+package godo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeconfigTemplate = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2EtZGF0YQ==
+    server: https://8d91899c-0739-4a1a-acc5-deadbeefbb8f.k8s.ondigitalocean.com
+  name: do-nyc1-antoine
+contexts:
+- context:
+    cluster: do-nyc1-antoine
+    user: do-nyc1-antoine-admin
+  name: do-nyc1-antoine
+current-context: do-nyc1-antoine
+kind: Config
+preferences: {}
+users:
+- name: do-nyc1-antoine-admin
+  user:
+%s
+`
+
+func testKubeconfig(user string) []byte {
+	return []byte(fmt.Sprintf(testKubeconfigTemplate, user))
+}
+
+func testJWT(t *testing.T, claims string) string {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".c2ln"
+}
+
+func testCertificate(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "antoine"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestKubernetesClusterConfig_TokenExpiry(t *testing.T) {
+	exp := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		user   string
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "jwt token",
+			user:   "    token: " + testJWT(t, fmt.Sprintf(`{"sub":"antoine","exp":%d}`, exp.Unix())),
+			want:   exp,
+			wantOK: true,
+		},
+		{
+			name:   "client certificate",
+			user:   "    client-certificate-data: " + testCertificate(t, exp),
+			want:   exp,
+			wantOK: true,
+		},
+		{
+			name: "jwt token without expiry",
+			user: "    token: " + testJWT(t, `{"sub":"antoine"}`),
+		},
+		{
+			name: "opaque token",
+			user: "    token: dop_v1_0123456789abcdef",
+		},
+		{
+			name: "exec plugin",
+			user: "    exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: doctl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig(tt.user)}
+			got, ok := config.TokenExpiry()
+			require.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesClusterConfig_TokenExpiry_InvalidYAML(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: []byte("some YAML")}
+	_, ok := config.TokenExpiry()
+	require.False(t, ok)
+}
+
+func TestKubernetesClusterConfig_Users(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig(`    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+      - kubernetes
+      - cluster
+      - kubeconfig
+      - exec-credential
+      - --version=v1beta1
+      - --context=default
+      - 8d91899c-0739-4a1a-acc5-deadbeefbb8f
+      command: doctl
+      env:
+      - name: DIGITALOCEAN_ACCESS_TOKEN
+        value: secret
+      provideClusterInfo: false`)}
+
+	users, err := config.Users()
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, &KubeconfigUser{
+		Name:        "do-nyc1-antoine-admin",
+		ExecCommand: "doctl",
+		ExecArgs: []string{
+			"kubernetes", "cluster", "kubeconfig", "exec-credential",
+			"--version=v1beta1", "--context=default", "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		},
+		ExecEnv: map[string]string{"DIGITALOCEAN_ACCESS_TOKEN": "secret"},
+	}, users[0])
+	assert.True(t, users[0].UsesExec())
+}
+
+func TestKubernetesClusterConfig_Users_Token(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+
+	users, err := config.Users()
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, &KubeconfigUser{Name: "do-nyc1-antoine-admin"}, users[0])
+	assert.False(t, users[0].UsesExec())
+}
+
+func TestKubernetesClusterConfig_Parse(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+
+	got, err := config.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, &KubeconfigFile{
+		Clusters: []*KubeconfigCluster{{
+			Name:                     "do-nyc1-antoine",
+			Server:                   "https://8d91899c-0739-4a1a-acc5-deadbeefbb8f.k8s.ondigitalocean.com",
+			CertificateAuthorityData: []byte("ca-data"),
+		}},
+		Contexts: []*KubeconfigContext{{
+			Name:    "do-nyc1-antoine",
+			Cluster: "do-nyc1-antoine",
+			User:    "do-nyc1-antoine-admin",
+		}},
+		Users:              []*KubeconfigUser{{Name: "do-nyc1-antoine-admin"}},
+		CurrentContextName: "do-nyc1-antoine",
+	}, got)
+	assert.Equal(t, got.Contexts[0], got.CurrentContext())
+	assert.Equal(t, "https://8d91899c-0739-4a1a-acc5-deadbeefbb8f.k8s.ondigitalocean.com", got.ServerURL())
+}
+
+func TestKubernetesClusterConfig_Parse_Errors(t *testing.T) {
+	_, err := (&KubernetesClusterConfig{KubeconfigYAML: []byte("some YAML")}).Parse()
+	require.Error(t, err)
+
+	_, err = (&KubernetesClusterConfig{KubeconfigYAML: []byte(`clusters:
+- name: broken
+  cluster:
+    server: https://example.com
+    certificate-authority-data: "!!not base64"`)}).Parse()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+}
+
+func TestKubeconfigFile_ServerURL(t *testing.T) {
+	f := &KubeconfigFile{
+		Clusters: []*KubeconfigCluster{{Name: "a", Server: "https://a.example.com"}},
+	}
+	assert.Nil(t, f.CurrentContext())
+	assert.Equal(t, "https://a.example.com", f.ServerURL(), "the only cluster is used without a current context")
+
+	f.Clusters = append(f.Clusters, &KubeconfigCluster{Name: "b", Server: "https://b.example.com"})
+	assert.Equal(t, "", f.ServerURL())
+
+	f.Contexts = []*KubeconfigContext{{Name: "ctx-b", Cluster: "b"}}
+	f.CurrentContextName = "ctx-b"
+	assert.Equal(t, "https://b.example.com", f.ServerURL())
+}
+
+const testExistingKubeconfig = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://staging.example.com
+  name: staging
+- cluster:
+    insecure-skip-tls-verify: true
+    server: https://localhost:6443
+  name: kind-local
+contexts:
+- context:
+    cluster: staging
+    namespace: web
+    user: staging-admin
+  name: staging
+- context:
+    cluster: kind-local
+    user: kind-local
+  name: kind-local
+current-context: staging
+kind: Config
+preferences: {}
+users:
+- name: staging-admin
+  user:
+    token: staging-token
+- name: kind-local
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+`
+
+func TestKubernetesClusterConfig_MergeInto(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+
+	merged, err := config.MergeInto([]byte(testExistingKubeconfig))
+	require.NoError(t, err)
+
+	got, err := (&KubernetesClusterConfig{KubeconfigYAML: merged}).Parse()
+	require.NoError(t, err)
+	assert.Equal(t, "staging", got.CurrentContextName)
+	require.Len(t, got.Clusters, 3)
+	assert.Equal(t, []string{"staging", "kind-local", "do-nyc1-antoine"}, []string{got.Clusters[0].Name, got.Clusters[1].Name, got.Clusters[2].Name})
+	require.Len(t, got.Contexts, 3)
+	assert.Equal(t, "web", got.Contexts[0].Namespace)
+	require.Len(t, got.Users, 3)
+	assert.Equal(t, "do-nyc1-antoine-admin", got.Users[2].Name)
+
+	// Fields that are not modeled survive the merge.
+	assert.Contains(t, string(merged), "insecure-skip-tls-verify: true")
+	assert.Contains(t, string(merged), "client-key-data: a2V5")
+	assert.Contains(t, string(merged), "preferences: {}")
+
+	// Merging again replaces the entries instead of duplicating them.
+	again, err := config.MergeIntoWithOptions(merged, &KubeconfigMergeOptions{SetCurrentContext: true})
+	require.NoError(t, err)
+	got, err = (&KubernetesClusterConfig{KubeconfigYAML: again}).Parse()
+	require.NoError(t, err)
+	assert.Len(t, got.Clusters, 3)
+	assert.Len(t, got.Contexts, 3)
+	assert.Len(t, got.Users, 3)
+	assert.Equal(t, "do-nyc1-antoine", got.CurrentContextName)
+}
+
+func TestKubernetesClusterConfig_MergeInto_Empty(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+
+	merged, err := config.MergeInto(nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.KubeconfigYAML, merged)
+
+	merged, err = config.MergeInto([]byte("\n  \n"))
+	require.NoError(t, err)
+	assert.Equal(t, config.KubeconfigYAML, merged)
+}
+
+func TestKubernetesClusterConfig_MergeInto_InvalidExisting(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+
+	_, err := config.MergeInto([]byte("some YAML"))
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_GetKubeConfigWithExpiry_ExpiresAt(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		expirySeconds, err := strconv.ParseInt(r.URL.Query().Get("expiry_seconds"), 10, 64)
+		require.NoError(t, err)
+		exp := time.Now().Add(time.Duration(expirySeconds) * time.Second).Unix()
+		w.Write(testKubeconfig("    token: " + testJWT(t, fmt.Sprintf(`{"exp":%d}`, exp))))
+	})
+
+	want := time.Now().Add(600 * time.Second)
+	got, _, err := kubeSvc.GetKubeConfigWithExpiry(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", 600)
+	require.NoError(t, err)
+	assert.WithinDuration(t, want, got.ExpiresAt, 5*time.Second)
+}
+
+func TestKubernetesClusters_GetKubeConfigWithExpiry_OpaqueToken(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.Write(testKubeconfig("    token: dop_v1_0123456789abcdef"))
+	})
+
+	before := time.Now()
+	got, _, err := kubeSvc.GetKubeConfigWithExpiry(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", 600)
+	require.NoError(t, err)
+	assert.False(t, got.ExpiresAt.Before(before.Add(600*time.Second)))
+	assert.False(t, got.ExpiresAt.After(time.Now().Add(600*time.Second)))
+
+	// Without a requested expiry, an opaque token's expiry is unknown.
+	got, _, err = kubeSvc.GetKubeConfig(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.NoError(t, err)
+	assert.True(t, got.ExpiresAt.IsZero())
+}
+
+func TestKubernetesClusters_GetKubeConfig_ExpiresAtUnknown(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testKubeconfig("    exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: doctl"))
+	})
+
+	got, _, err := kubeSvc.GetKubeConfig(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.NoError(t, err)
+	assert.True(t, got.ExpiresAt.IsZero())
+}
+
+func TestKubernetesClusterConfig_CurrentContextName(t *testing.T) {
+	config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig("    token: some-token")}
+	got, err := config.CurrentContextName()
+	require.NoError(t, err)
+	assert.Equal(t, "do-nyc1-antoine", got)
+
+	_, err = (&KubernetesClusterConfig{KubeconfigYAML: []byte("apiVersion: v1\nkind: Config\n")}).CurrentContextName()
+	require.Error(t, err)
+
+	_, err = (&KubernetesClusterConfig{KubeconfigYAML: []byte("some YAML")}).CurrentContextName()
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_GetKubeConfigWithOptions_ContextName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.Write(testKubeconfig("    token: some-token"))
+	})
+
+	got, _, err := kubeSvc.GetKubeConfigWithOptions(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", &KubernetesKubeConfigOptions{ContextName: "team-a-prod"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(got.KubeconfigYAML), "do-nyc1-antoine")
+
+	f, err := got.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, &KubeconfigContext{Name: "team-a-prod", Cluster: "team-a-prod", User: "team-a-prod"}, f.CurrentContext())
+	require.NotNil(t, f.Cluster("team-a-prod"))
+	assert.Equal(t, "https://8d91899c-0739-4a1a-acc5-deadbeefbb8f.k8s.ondigitalocean.com", f.ServerURL())
+	require.Len(t, f.Users, 1)
+	assert.Equal(t, "team-a-prod", f.Users[0].Name)
+	assert.Contains(t, string(got.KubeconfigYAML), "token: some-token")
+}
+
+func TestKubernetesClusterConfig_Redacted(t *testing.T) {
+	config := &KubernetesClusterConfig{
+		KubeconfigYAML: testKubeconfig("    token: secret-token\n    client-certificate-data: c2VjcmV0LWNlcnQ=\n    client-key-data: c2VjcmV0LWtleQ=="),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	got, err := config.Redacted()
+	require.NoError(t, err)
+	for _, secret := range []string{"secret-token", "c2VjcmV0LWNlcnQ=", "c2VjcmV0LWtleQ==", "client-certificate-data", "client-key-data"} {
+		assert.NotContains(t, string(got.KubeconfigYAML), secret)
+	}
+	assert.True(t, got.ExpiresAt.IsZero())
+
+	f, err := got.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, "https://8d91899c-0739-4a1a-acc5-deadbeefbb8f.k8s.ondigitalocean.com", f.ServerURL())
+	assert.Equal(t, []byte("ca-data"), f.Cluster("do-nyc1-antoine").CertificateAuthorityData)
+	assert.Equal(t, "do-nyc1-antoine-admin", f.CurrentContext().User)
+
+	// The original is left untouched.
+	assert.Contains(t, string(config.KubeconfigYAML), "secret-token")
+
+	_, err = (&KubernetesClusterConfig{KubeconfigYAML: []byte("some YAML")}).Redacted()
+	require.Error(t, err)
+}
+
+func TestKubernetesClusterConfig_Redacted_Fields(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   string
+		secret string
+	}{
+		{
+			name:   "password",
+			user:   "    username: antoine\n    password: secret-password",
+			secret: "secret-password",
+		},
+		{
+			name:   "token file",
+			user:   "    token-file: /secret/token",
+			secret: "/secret/token",
+		},
+		{
+			name:   "client certificate path",
+			user:   "    client-certificate: /secret/client.crt",
+			secret: "/secret/client.crt",
+		},
+		{
+			name:   "client key path",
+			user:   "    client-key: /secret/client.key",
+			secret: "/secret/client.key",
+		},
+		{
+			name:   "auth provider id token",
+			user:   "    auth-provider:\n      name: oidc\n      config:\n        id-token: secret-id-token",
+			secret: "secret-id-token",
+		},
+		{
+			name:   "auth provider refresh token",
+			user:   "    auth-provider:\n      name: oidc\n      config:\n        refresh-token: secret-refresh-token",
+			secret: "secret-refresh-token",
+		},
+		{
+			name:   "exec env",
+			user:   "    exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: doctl\n      env:\n      - name: DIGITALOCEAN_ACCESS_TOKEN\n        value: secret-env-token",
+			secret: "secret-env-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &KubernetesClusterConfig{KubeconfigYAML: testKubeconfig(tt.user)}
+			require.Contains(t, string(config.KubeconfigYAML), tt.secret)
+
+			got, err := config.Redacted()
+			require.NoError(t, err)
+			assert.NotContains(t, string(got.KubeconfigYAML), tt.secret)
+
+			_, err = got.Parse()
+			require.NoError(t, err)
+		})
+	}
+}
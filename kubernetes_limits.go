@@ -0,0 +1,113 @@
+package godo
+
+import (
+	"context"
+	"fmt"
+)
+
+// KubernetesLimits describes the account limits that constrain Kubernetes
+// resources. Every node is a droplet, so the number of nodes is bounded by the
+// account's droplet limit. A zero limit means the limit is unknown and is not
+// enforced.
+type KubernetesLimits struct {
+	// DropletLimit is the maximum number of droplets of the account.
+	DropletLimit int
+	// DropletsInUse is the number of droplets the account currently has,
+	// including Kubernetes nodes.
+	DropletsInUse int
+}
+
+// AvailableDroplets returns how many more droplets the account can create, or
+// -1 if the droplet limit is unknown.
+func (l *KubernetesLimits) AvailableDroplets() int {
+	if l.DropletLimit <= 0 {
+		return -1
+	}
+	if available := l.DropletLimit - l.DropletsInUse; available > 0 {
+		return available
+	}
+	return 0
+}
+
+// ValidateWithLimits checks the node pool create request with Validate and
+// then checks that the largest size the node pool can reach, MaxNodes for
+// autoscaling pools and Count otherwise, fits within the droplets available
+// to the account. An unknown droplet limit is not checked.
+func (r *KubernetesNodePoolCreateRequest) ValidateWithLimits(limits *KubernetesLimits) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if limits == nil {
+		return NewArgError("limits", "cannot be nil")
+	}
+
+	field, size := "Count", r.Count
+	if r.AutoScale {
+		field, size = "MaxNodes", r.MaxNodes
+	}
+	if available := limits.AvailableDroplets(); available >= 0 && size > available {
+		return NewArgError(field, fmt.Sprintf("%d exceeds the %d droplets available to the account", size, available))
+	}
+	return nil
+}
+
+// GetLimits reads the account limits that constrain Kubernetes resources from
+// the account and the droplets it currently has. The API does not expose
+// limits on the number of node pools of a cluster or of nodes of a node pool,
+// so these are not included.
+func (svc *KubernetesServiceOp) GetLimits(ctx context.Context) (*KubernetesLimits, *Response, error) {
+	account, resp, err := svc.client.Account.Get(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	_, resp, err = svc.client.Droplets.List(ctx, &ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	limits := &KubernetesLimits{DropletLimit: account.DropletLimit}
+	if resp.Meta != nil {
+		limits.DropletsInUse = resp.Meta.Total
+	}
+	return limits, resp, nil
+}
+
+// CanScaleNodePool reports whether the node pool can be scaled to target nodes
+// without exceeding the account's droplet limit. If it cannot, the returned
+// string explains why.
+func (svc *KubernetesServiceOp) CanScaleNodePool(ctx context.Context, clusterID, poolID string, target int, limits *KubernetesLimits) (bool, string, *Response, error) {
+	if target < 0 {
+		return false, "", nil, NewArgError("target", "cannot be less than 0")
+	}
+	if limits == nil {
+		return false, "", nil, NewArgError("limits", "cannot be nil")
+	}
+
+	pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+	if err != nil {
+		return false, "", resp, err
+	}
+
+	needed := target - pool.Count
+	available := limits.AvailableDroplets()
+	if needed <= 0 || available < 0 || needed <= available {
+		return true, "", resp, nil
+	}
+	reason := fmt.Sprintf("scaling node pool %s from %d to %d nodes needs %d more droplets, but only %d of the account's %d droplets are available",
+		pool.Name, pool.Count, target, needed, available, limits.DropletLimit)
+	return false, reason, resp, nil
+}
+
+// CreateNodePoolWithinLimits creates a node pool like CreateNodePool, but
+// first checks the request with ValidateWithLimits, so that a node pool that
+// could outgrow the droplets available to the account is not created. The
+// API does not expose a limit on the number of node pools of a cluster, so
+// that is left for the API to enforce.
+func (svc *KubernetesServiceOp) CreateNodePoolWithinLimits(ctx context.Context, clusterID string, create *KubernetesNodePoolCreateRequest, limits *KubernetesLimits) (*KubernetesNodePool, *Response, error) {
+	if create != nil {
+		if err := create.ValidateWithLimits(limits); err != nil {
+			return nil, nil, err
+		}
+	}
+	return svc.CreateNodePool(ctx, clusterID, create)
+}
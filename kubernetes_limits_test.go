@@ -0,0 +1,175 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClusters_GetLimits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/account", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"account": {"droplet_limit": 25}}`)
+	})
+	mux.HandleFunc("/v2/droplets", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "1", r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, `{"droplets": [{"id": 1}], "meta": {"total": 18}}`)
+	})
+
+	got, _, err := kubeSvc.GetLimits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesLimits{DropletLimit: 25, DropletsInUse: 18}, got)
+	assert.Equal(t, 7, got.AvailableDroplets())
+}
+
+func TestKubernetesLimits_AvailableDroplets(t *testing.T) {
+	assert.Equal(t, 5, (&KubernetesLimits{DropletLimit: 10, DropletsInUse: 5}).AvailableDroplets())
+	assert.Equal(t, 0, (&KubernetesLimits{DropletLimit: 10, DropletsInUse: 12}).AvailableDroplets())
+	assert.Equal(t, -1, (&KubernetesLimits{DropletsInUse: 12}).AvailableDroplets())
+}
+
+func TestKubernetesClusters_CanScaleNodePool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-0739-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8a", "name": "pool-a", "count": 3}}`)
+	})
+
+	limits := &KubernetesLimits{DropletLimit: 10, DropletsInUse: 8}
+	tests := []struct {
+		name   string
+		target int
+		limits *KubernetesLimits
+		want   bool
+	}{
+		{name: "within quota", target: 5, limits: limits, want: true},
+		{name: "scale down", target: 1, limits: limits, want: true},
+		{name: "exceeds quota", target: 6, limits: limits, want: false},
+		{name: "unknown limit", target: 100, limits: &KubernetesLimits{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason, _, err := kubeSvc.CanScaleNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a", tt.target, tt.limits)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.Empty(t, reason)
+			} else {
+				assert.Equal(t, "scaling node pool pool-a from 3 to 6 nodes needs 3 more droplets, but only 2 of the account's 10 droplets are available", reason)
+			}
+		})
+	}
+}
+
+func TestKubernetesClusters_CanScaleNodePool_InvalidArgs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	_, _, _, err := kubeSvc.CanScaleNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a", -1, &KubernetesLimits{})
+	require.Error(t, err)
+	_, _, _, err = kubeSvc.CanScaleNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a", 3, nil)
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_CreateNodePoolWithinLimits(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	created := false
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		created = true
+		fmt.Fprint(w, `{"node_pool": {"id": "pool-3", "name": "pool-c"}}`)
+	})
+
+	create := &KubernetesNodePoolCreateRequest{Name: "pool-c", Size: "s-1vcpu-2gb", Count: 3}
+
+	_, _, err := kubeSvc.CreateNodePoolWithinLimits(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", create, &KubernetesLimits{DropletLimit: 25, DropletsInUse: 23})
+	require.Equal(t, NewArgError("Count", "3 exceeds the 2 droplets available to the account"), err)
+	assert.False(t, created)
+
+	got, _, err := kubeSvc.CreateNodePoolWithinLimits(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", create, &KubernetesLimits{DropletLimit: 25, DropletsInUse: 22})
+	require.NoError(t, err)
+	assert.Equal(t, "pool-3", got.ID)
+	assert.True(t, created)
+}
+
+func TestKubernetesNodePoolCreateRequest_ValidateWithLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *KubernetesNodePoolCreateRequest
+		limits *KubernetesLimits
+		want   error
+	}{
+		{
+			name:   "within limits",
+			req:    &KubernetesNodePoolCreateRequest{AutoScale: true, MinNodes: 1, MaxNodes: 10},
+			limits: &KubernetesLimits{DropletLimit: 25, DropletsInUse: 5},
+		},
+		{
+			name:   "unknown limits",
+			req:    &KubernetesNodePoolCreateRequest{AutoScale: true, MinNodes: 1, MaxNodes: 1000},
+			limits: &KubernetesLimits{},
+		},
+		{
+			name:   "max nodes exceeds available droplets",
+			req:    &KubernetesNodePoolCreateRequest{AutoScale: true, MinNodes: 1, MaxNodes: 10},
+			limits: &KubernetesLimits{DropletLimit: 25, DropletsInUse: 20},
+			want:   NewArgError("MaxNodes", "10 exceeds the 5 droplets available to the account"),
+		},
+		{
+			name:   "count exceeds available droplets",
+			req:    &KubernetesNodePoolCreateRequest{Count: 30},
+			limits: &KubernetesLimits{DropletLimit: 25},
+			want:   NewArgError("Count", "30 exceeds the 25 droplets available to the account"),
+		},
+		{
+			name:   "invalid request",
+			req:    &KubernetesNodePoolCreateRequest{AutoScale: true, MinNodes: 6, MaxNodes: 5},
+			limits: &KubernetesLimits{},
+			want:   NewArgError("MinNodes", "cannot be greater than MaxNodes"),
+		},
+		{
+			name: "nil limits",
+			req:  &KubernetesNodePoolCreateRequest{Count: 1},
+			want: NewArgError("limits", "cannot be nil"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.req.ValidateWithLimits(tt.limits))
+		})
+	}
+}
+
+func TestKubernetesClusters_CreateNodePoolWithinLimits_MaxNodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for a node pool exceeding the limits")
+	})
+
+	create := &KubernetesNodePoolCreateRequest{Name: "pool-c", Size: "s-1vcpu-2gb", AutoScale: true, MinNodes: 1, MaxNodes: 50}
+	_, _, err := kubeSvc.CreateNodePoolWithinLimits(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", create, &KubernetesLimits{DropletLimit: 25, DropletsInUse: 5})
+	require.Equal(t, NewArgError("MaxNodes", "50 exceeds the 20 droplets available to the account"), err)
+}
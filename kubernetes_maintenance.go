@@ -0,0 +1,96 @@
+package godo
+
+import (
+	"time"
+)
+
+// defaultKubernetesMaintenanceDuration is the length of a maintenance window
+// whose policy does not specify a duration.
+const defaultKubernetesMaintenanceDuration = 4 * time.Hour
+
+// NextWindow returns the start and end of the first maintenance window that
+// starts at or after the given time. StartTime is interpreted in UTC, and the
+// window lasts for Duration, or 4 hours if Duration is empty. For
+// KubernetesMaintenanceDayAny, the window opens every day.
+func (p KubernetesMaintenancePolicy) NextWindow(after time.Time) (start, end time.Time, err error) {
+	return p.nextWindowIn(after, time.UTC)
+}
+
+// nextWindowIn works like NextWindow, but interprets StartTime and Day in loc.
+func (p KubernetesMaintenancePolicy) nextWindowIn(after time.Time, loc *time.Location) (start, end time.Time, err error) {
+	if err := p.Validate(); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	length, err := p.windowDuration()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	startTime, err := time.Parse(kubernetesMaintenanceStartTimeLayout, p.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	after = after.In(loc)
+	start = time.Date(after.Year(), after.Month(), after.Day(), startTime.Hour(), startTime.Minute(), 0, 0, loc)
+
+	step := 1
+	if weekday, ok := p.Day.Weekday(); ok {
+		step = 7
+		start = start.AddDate(0, 0, daysUntil(start.Weekday(), weekday))
+	}
+	if start.Before(after) {
+		start = start.AddDate(0, 0, step)
+	}
+	return start, start.Add(length), nil
+}
+
+// InMaintenanceWindow reports whether now falls within a maintenance window of
+// the cluster's maintenance policy, with the window's start inclusive and its
+// end exclusive. The policy's StartTime and Day are interpreted in loc, or in
+// UTC, as the API does, if loc is nil. It returns false if the cluster has no
+// valid maintenance policy.
+func (kc *KubernetesCluster) InMaintenanceWindow(now time.Time, loc *time.Location) bool {
+	p := kc.MaintenancePolicy
+	if p == nil {
+		return false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	length, err := p.windowDuration()
+	if err != nil {
+		return false
+	}
+	// The window containing now, if any, is the first one to start after
+	// now minus the window's length.
+	start, end, err := p.nextWindowIn(now.Add(-length), loc)
+	if err != nil {
+		return false
+	}
+	return !now.Before(start) && now.Before(end)
+}
+
+// windowDuration returns the length of the maintenance window.
+func (p KubernetesMaintenancePolicy) windowDuration() (time.Duration, error) {
+	if p.Duration == "" {
+		return defaultKubernetesMaintenanceDuration, nil
+	}
+	return time.ParseDuration(p.Duration)
+}
+
+// Weekday returns the time.Weekday of the maintenance day. It returns false for
+// KubernetesMaintenanceDayAny and for invalid days.
+func (k KubernetesMaintenancePolicyDay) Weekday() (time.Weekday, bool) {
+	if k < KubernetesMaintenanceDayMonday || k > KubernetesMaintenanceDaySunday {
+		return 0, false
+	}
+	// Monday through Saturday share their numbering with time.Weekday, and
+	// Sunday wraps around to 0.
+	return time.Weekday(int(k) % 7), true
+}
+
+// daysUntil returns the number of days from one weekday to the next occurrence
+// of another, in [0, 6].
+func daysUntil(from, to time.Weekday) int {
+	return (int(to) - int(from) + 7) % 7
+}
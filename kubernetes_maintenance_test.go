@@ -0,0 +1,227 @@
+package godo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesMaintenancePolicy_NextWindow(t *testing.T) {
+	// 2024-06-05 is a Wednesday.
+	wednesdayNoon := time.Date(2024, 6, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		policy    KubernetesMaintenancePolicy
+		after     time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "later the same week",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Duration: "4h0m0s", Day: KubernetesMaintenanceDayFriday},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 7, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 7, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "wraps into next week",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayMonday},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 10, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "sunday",
+			policy:    KubernetesMaintenancePolicy{StartTime: "23:30", Duration: "1h", Day: KubernetesMaintenanceDaySunday},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 9, 23, 30, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 10, 0, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "same day already passed",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayWednesday},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 12, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 12, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "starts exactly now",
+			policy:    KubernetesMaintenancePolicy{StartTime: "12:00", Day: KubernetesMaintenanceDayWednesday},
+			after:     wednesdayNoon,
+			wantStart: wednesdayNoon,
+			wantEnd:   time.Date(2024, 6, 5, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "any day later today",
+			policy:    KubernetesMaintenancePolicy{StartTime: "18:00", Day: KubernetesMaintenanceDayAny},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 5, 18, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "any day tomorrow",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayAny},
+			after:     wednesdayNoon,
+			wantStart: time.Date(2024, 6, 6, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 6, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "any day across year end",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayAny},
+			after:     time.Date(2024, 12, 31, 22, 0, 0, 0, time.UTC),
+			wantStart: time.Date(2025, 1, 1, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "after in another time zone",
+			policy:    KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayThursday},
+			after:     time.Date(2024, 6, 5, 23, 0, 0, 0, time.FixedZone("UTC-6", -6*60*60)),
+			wantStart: time.Date(2024, 6, 13, 4, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 6, 13, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := tt.policy.NextWindow(tt.after)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestKubernetesMaintenancePolicy_NextWindow_Invalid(t *testing.T) {
+	_, _, err := KubernetesMaintenancePolicy{StartTime: "4am"}.NextWindow(time.Now())
+	require.Error(t, err)
+}
+
+func TestKubernetesMaintenancePolicyDay_Weekday(t *testing.T) {
+	tests := []struct {
+		day    KubernetesMaintenancePolicyDay
+		want   time.Weekday
+		wantOK bool
+	}{
+		{KubernetesMaintenanceDayMonday, time.Monday, true},
+		{KubernetesMaintenanceDayTuesday, time.Tuesday, true},
+		{KubernetesMaintenanceDayWednesday, time.Wednesday, true},
+		{KubernetesMaintenanceDayThursday, time.Thursday, true},
+		{KubernetesMaintenanceDayFriday, time.Friday, true},
+		{KubernetesMaintenanceDaySaturday, time.Saturday, true},
+		{KubernetesMaintenanceDaySunday, time.Sunday, true},
+		{KubernetesMaintenanceDayAny, 0, false},
+		{KubernetesMaintenancePolicyDay(100), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.day.String(), func(t *testing.T) {
+			got, ok := tt.day.Weekday()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesCluster_InMaintenanceWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2024-06-05 is a Wednesday.
+	tests := []struct {
+		name   string
+		policy *KubernetesMaintenancePolicy
+		now    time.Time
+		loc    *time.Location
+		want   bool
+	}{
+		{
+			name:   "at start",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayWednesday},
+			now:    time.Date(2024, 6, 5, 4, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "just before start",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayWednesday},
+			now:    time.Date(2024, 6, 5, 3, 59, 59, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "just before end",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Duration: "4h", Day: KubernetesMaintenanceDayWednesday},
+			now:    time.Date(2024, 6, 5, 7, 59, 59, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "at end",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Duration: "4h", Day: KubernetesMaintenanceDayWednesday},
+			now:    time.Date(2024, 6, 5, 8, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "other day",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayThursday},
+			now:    time.Date(2024, 6, 5, 5, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "spans midnight",
+			policy: &KubernetesMaintenancePolicy{StartTime: "23:00", Duration: "2h", Day: KubernetesMaintenanceDaySunday},
+			now:    time.Date(2024, 6, 10, 0, 30, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "any day",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayAny},
+			now:    time.Date(2024, 6, 8, 6, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "any day outside window",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayAny},
+			now:    time.Date(2024, 6, 8, 9, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name:   "location",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:00", Day: KubernetesMaintenanceDayWednesday},
+			// 04:30 on Wednesday in New York.
+			now:  time.Date(2024, 6, 5, 8, 30, 0, 0, time.UTC),
+			loc:  newYork,
+			want: true,
+		},
+		{
+			name:   "location shifts the day",
+			policy: &KubernetesMaintenancePolicy{StartTime: "22:00", Day: KubernetesMaintenanceDayTuesday},
+			// 22:30 on Tuesday in New York, but already Wednesday in UTC.
+			now:  time.Date(2024, 6, 5, 2, 30, 0, 0, time.UTC),
+			loc:  newYork,
+			want: true,
+		},
+		{
+			name:   "UTC by default",
+			policy: &KubernetesMaintenancePolicy{StartTime: "22:00", Day: KubernetesMaintenanceDayTuesday},
+			now:    time.Date(2024, 6, 5, 2, 30, 0, 0, time.UTC),
+			want:   false,
+		},
+		{
+			name: "no policy",
+			now:  time.Date(2024, 6, 5, 4, 0, 0, 0, time.UTC),
+			want: false,
+		},
+		{
+			name:   "invalid policy",
+			policy: &KubernetesMaintenancePolicy{StartTime: "4am"},
+			now:    time.Date(2024, 6, 5, 4, 0, 0, 0, time.UTC),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &KubernetesCluster{MaintenancePolicy: tt.policy}
+			assert.Equal(t, tt.want, kc.InMaintenanceWindow(tt.now, tt.loc))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package godo
+
+import "strings"
+
+// kubernetesGPUSizePrefix is the prefix of the slugs of GPU node sizes.
+const kubernetesGPUSizePrefix = "gpu-"
+
+// FilterSizes returns the node sizes for which pred returns true.
+func (o *KubernetesOptions) FilterSizes(pred func(KubernetesNodeSize) bool) []*KubernetesNodeSize {
+	var sizes []*KubernetesNodeSize
+	for _, size := range o.Sizes {
+		if size != nil && pred(*size) {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// GPUSizes returns the GPU node sizes, i.e. those whose slug starts with
+// "gpu-".
+func (o *KubernetesOptions) GPUSizes() []*KubernetesNodeSize {
+	return o.FilterSizes(func(size KubernetesNodeSize) bool {
+		return strings.HasPrefix(size.Slug, kubernetesGPUSizePrefix)
+	})
+}
+
+// RegionBySlug returns the region with the given slug, such as nyc1, and
+// whether it is listed in the options, i.e. whether clusters can be created
+// in it.
+func (o *KubernetesOptions) RegionBySlug(slug string) (*KubernetesRegion, bool) {
+	for _, r := range o.Regions {
+		if r != nil && r.Slug == slug {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// HasRegion reports whether clusters can be created in the region with the
+// given slug.
+func (o *KubernetesOptions) HasRegion(slug string) bool {
+	_, ok := o.RegionBySlug(slug)
+	return ok
+}
+
+// SizeBySlug returns the node size with the given Droplet size slug, such as
+// s-2vcpu-4gb, and whether it is listed in the options, i.e. whether node
+// pools can use it. The size's Name is its human-readable description.
+func (o *KubernetesOptions) SizeBySlug(slug string) (*KubernetesNodeSize, bool) {
+	for _, s := range o.Sizes {
+		if s != nil && s.Slug == slug {
+			return s, true
+		}
+	}
+	return nil, false
+}
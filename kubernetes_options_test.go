@@ -0,0 +1,79 @@
+package godo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKubernetesOptions = &KubernetesOptions{
+	Versions: []*KubernetesVersion{
+		{Slug: "1.30.2-do.0", KubernetesVersion: "1.30.2"},
+	},
+	Regions: []*KubernetesRegion{
+		{Name: "New York 1", Slug: "nyc1"},
+		{Name: "Toronto 1", Slug: "tor1"},
+	},
+	Sizes: []*KubernetesNodeSize{
+		{Name: "s-1vcpu-2gb", Slug: "s-1vcpu-2gb"},
+		{Name: "gpu-h100x1-80gb", Slug: "gpu-h100x1-80gb"},
+		{Name: "c-4", Slug: "c-4"},
+		{Name: "gpu-mi300x1-192gb", Slug: "gpu-mi300x1-192gb"},
+	},
+}
+
+func TestKubernetesOptions_FilterSizes(t *testing.T) {
+	got := testKubernetesOptions.FilterSizes(func(size KubernetesNodeSize) bool {
+		return strings.HasPrefix(size.Slug, "c-")
+	})
+	assert.Equal(t, []*KubernetesNodeSize{{Name: "c-4", Slug: "c-4"}}, got)
+
+	got = testKubernetesOptions.FilterSizes(func(KubernetesNodeSize) bool { return false })
+	assert.Empty(t, got)
+}
+
+func TestKubernetesOptions_GPUSizes(t *testing.T) {
+	assert.Equal(t, []*KubernetesNodeSize{
+		{Name: "gpu-h100x1-80gb", Slug: "gpu-h100x1-80gb"},
+		{Name: "gpu-mi300x1-192gb", Slug: "gpu-mi300x1-192gb"},
+	}, testKubernetesOptions.GPUSizes())
+}
+
+func TestKubernetesOptions_RegionBySlug(t *testing.T) {
+	options := &KubernetesOptions{
+		Regions: []*KubernetesRegion{
+			{Name: "New York 1", Slug: "nyc1"},
+			{Name: "Amsterdam 3", Slug: "ams3"},
+		},
+	}
+
+	got, ok := options.RegionBySlug("ams3")
+	require.True(t, ok)
+	assert.Equal(t, &KubernetesRegion{Name: "Amsterdam 3", Slug: "ams3"}, got)
+	assert.True(t, options.HasRegion("nyc1"))
+
+	got, ok = options.RegionBySlug("sfo1")
+	assert.False(t, ok)
+	assert.Nil(t, got)
+	assert.False(t, options.HasRegion("sfo1"))
+	assert.False(t, options.HasRegion("NYC1"))
+}
+
+func TestKubernetesOptions_SizeBySlug(t *testing.T) {
+	options := &KubernetesOptions{
+		Sizes: []*KubernetesNodeSize{
+			{Name: "s-1vcpu-2gb", Slug: "s-1vcpu-2gb"},
+			{Name: "Basic 2 vCPUs 4 GB", Slug: "s-2vcpu-4gb"},
+		},
+	}
+
+	got, ok := options.SizeBySlug("s-2vcpu-4gb")
+	require.True(t, ok)
+	assert.Equal(t, "Basic 2 vCPUs 4 GB", got.Name)
+
+	got, ok = options.SizeBySlug("g-8vcpu-32gb")
+	assert.False(t, ok)
+	assert.Nil(t, got)
+}
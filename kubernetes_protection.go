@@ -0,0 +1,43 @@
+package godo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrKubernetesNodePoolProtected is returned when DeleteNodePool refuses to
+// delete a node pool that carries a protected label.
+var ErrKubernetesNodePoolProtected = errors.New("kubernetes node pool is protected")
+
+// WithKubernetesProtectedNodePoolLabels makes DeleteNodePool, and with it
+// DeleteNodePools, refuse to delete node pools that carry any of the given
+// labels with the given value, e.g. {"protected": "true"}. ForceDeleteNodePool
+// bypasses the check.
+func WithKubernetesProtectedNodePoolLabels(labels map[string]string) ClientOpt {
+	return func(c *Client) error {
+		c.KubernetesProtectedNodePoolLabels = labels
+		return nil
+	}
+}
+
+// checkNodePoolNotProtected returns an error wrapping
+// ErrKubernetesNodePoolProtected if the node pool carries one of the client's
+// protected labels. No request is made if no labels are configured.
+func (svc *KubernetesServiceOp) checkNodePoolNotProtected(ctx context.Context, clusterID, poolID string) (*Response, error) {
+	protected := svc.client.KubernetesProtectedNodePoolLabels
+	if len(protected) == 0 {
+		return nil, nil
+	}
+
+	pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+	if err != nil {
+		return resp, err
+	}
+	for key, value := range protected {
+		if v, ok := pool.Labels[key]; ok && v == value {
+			return resp, fmt.Errorf("node pool %s has label %s=%s: %w", poolID, key, value, ErrKubernetesNodePoolProtected)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,60 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesProtectedNodePoolLabels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	require.NoError(t, WithKubernetesProtectedNodePoolLabels(map[string]string{"protected": "true"})(client))
+	kubeSvc := client.Kubernetes
+
+	deletes := 0
+	handle := func(poolID, labels string) {
+		mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/node_pools/"+poolID, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprintf(w, `{"node_pool": {"id": "%s", "labels": %s}}`, poolID, labels)
+			case http.MethodDelete:
+				deletes++
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+	}
+	handle("system-pool", `{"protected": "true"}`)
+	handle("workers", `{"protected": "false"}`)
+
+	_, err := kubeSvc.DeleteNodePool(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", "system-pool")
+	assert.ErrorIs(t, err, ErrKubernetesNodePoolProtected)
+	assert.Equal(t, 0, deletes)
+
+	_, err = kubeSvc.DeleteNodePool(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", "workers")
+	require.NoError(t, err)
+	assert.Equal(t, 1, deletes)
+
+	_, err = kubeSvc.ForceDeleteNodePool(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", "system-pool")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deletes)
+}
+
+func TestKubernetesProtectedNodePoolLabels_Disabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/node_pools/system-pool", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := kubeSvc.DeleteNodePool(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", "system-pool")
+	require.NoError(t, err)
+}
@@ -0,0 +1,453 @@
+package godo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EnsureCluster makes sure a Kubernetes cluster matching spec exists. The
+// cluster is looked up by name and created if there is none. Otherwise, drift
+// from the spec is reconciled: tags, maintenance policy, auto and surge
+// upgrades, HA, control plane firewall and autoscaler configuration are
+// updated on the cluster, keeping its other settings, node pools missing from
+// the cluster are created and node pools whose count, autoscaling, labels,
+// tags or taints differ are updated. The k8s and k8s:* tags added by the API are ignored when
+// comparing tags. Node pools that are not part of the spec are left in
+// place, and fields that cannot be changed after creation, such as the region
+// or a node pool's size, are ignored. The returned bool reports whether the
+// cluster was created.
+//
+// The spec is checked with Validate before any request is made. As the API
+// cannot clear all labels or user tags through an update, an error matching
+// ErrKubernetesClearUnsupported is returned if the spec has none where the
+// cluster or one of its node pools has some. As HA cannot be turned off, an
+// error matching ErrKubernetesHADowngradeUnsupported is returned if the spec
+// does not enable it on a highly available cluster.
+func (svc *KubernetesServiceOp) EnsureCluster(ctx context.Context, spec *KubernetesClusterCreateRequest) (*KubernetesCluster, bool, *Response, error) {
+	if spec == nil {
+		return nil, false, nil, NewArgError("spec", "cannot be nil")
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, false, nil, err
+	}
+
+	cluster, resp, err := svc.findClusterByName(ctx, spec.Name)
+	if err != nil {
+		return nil, false, resp, err
+	}
+	if cluster == nil {
+		cluster, resp, err = svc.Create(ctx, spec)
+		if err != nil {
+			return nil, false, resp, err
+		}
+		return cluster, true, resp, nil
+	}
+
+	update, err := clusterUpdateForSpec(cluster, spec)
+	if err != nil {
+		return nil, false, resp, err
+	}
+	if update != nil {
+		cluster, resp, err = svc.Update(ctx, cluster.ID, update)
+		if err != nil {
+			return nil, false, resp, err
+		}
+	}
+
+	pools := make(map[string]*KubernetesNodePool, len(cluster.NodePools))
+	for _, pool := range cluster.NodePools {
+		pools[pool.Name] = pool
+	}
+	changedPools := false
+	for _, want := range spec.NodePools {
+		have, ok := pools[want.Name]
+		if !ok {
+			if _, resp, err = svc.CreateNodePool(ctx, cluster.ID, want); err != nil {
+				return nil, false, resp, err
+			}
+			changedPools = true
+			continue
+		}
+		update, err := nodePoolUpdateForSpec(have, want)
+		if err != nil {
+			return nil, false, resp, fmt.Errorf("node pool %s: %w", want.Name, err)
+		}
+		if update != nil {
+			if _, resp, err = svc.UpdateNodePool(ctx, cluster.ID, have.ID, update); err != nil {
+				return nil, false, resp, err
+			}
+			changedPools = true
+		}
+	}
+
+	if changedPools {
+		cluster, resp, err = svc.Get(ctx, cluster.ID)
+		if err != nil {
+			return nil, false, resp, err
+		}
+	}
+	return cluster, false, resp, nil
+}
+
+// NodePoolReconcileOptions configures ReconcileNodePoolsWithOptions.
+type NodePoolReconcileOptions struct {
+	// DeleteExtra deletes node pools of the cluster that are not desired.
+	// By default they are only reported in NodePoolReconcileResult.Extra.
+	DeleteExtra bool
+}
+
+// NodePoolReconcileResult reports the actions taken by ReconcileNodePools.
+type NodePoolReconcileResult struct {
+	// Created holds the node pools that were created.
+	Created []*KubernetesNodePool
+	// Updated holds the node pools that were updated, as returned by the
+	// update.
+	Updated []*KubernetesNodePool
+	// Deleted holds the names of the node pools that were deleted.
+	Deleted []string
+	// Extra holds the names of the node pools that are not desired but were
+	// left in place because deletes were not enabled.
+	Extra []string
+}
+
+// ReconcileNodePools brings the node pools of a cluster in line with desired,
+// matching node pools by name: missing node pools are created and drifted ones
+// are updated, like in EnsureCluster. Node pools that are not desired are left
+// in place and reported in the result; use ReconcileNodePoolsWithOptions to
+// delete them. As in EnsureCluster, an error matching
+// ErrKubernetesClearUnsupported is returned for a desired node pool without
+// labels or user tags if the existing one has some.
+func (svc *KubernetesServiceOp) ReconcileNodePools(ctx context.Context, clusterID string, desired []*KubernetesNodePool) (*NodePoolReconcileResult, *Response, error) {
+	return svc.ReconcileNodePoolsWithOptions(ctx, clusterID, desired, nil)
+}
+
+// ReconcileNodePoolsWithOptions works like ReconcileNodePools, with options to
+// delete node pools that are not desired. Reconciling stops at the first
+// failed request; the actions taken until then are returned with the error.
+func (svc *KubernetesServiceOp) ReconcileNodePoolsWithOptions(ctx context.Context, clusterID string, desired []*KubernetesNodePool, opts *NodePoolReconcileOptions) (*NodePoolReconcileResult, *Response, error) {
+	wanted := make(map[string]bool, len(desired))
+	for i, pool := range desired {
+		if pool == nil || pool.Name == "" {
+			return nil, nil, NewArgError(fmt.Sprintf("desired[%d].Name", i), "cannot be an empty string")
+		}
+		if wanted[pool.Name] {
+			return nil, nil, NewArgError(fmt.Sprintf("desired[%d].Name", i), fmt.Sprintf("%q is not unique", pool.Name))
+		}
+		wanted[pool.Name] = true
+	}
+
+	existing, resp, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	pools := make(map[string]*KubernetesNodePool, len(existing))
+	for _, pool := range existing {
+		pools[pool.Name] = pool
+	}
+
+	result := &NodePoolReconcileResult{}
+	for _, want := range desired {
+		spec := want.ToCreateRequest()
+		have, ok := pools[want.Name]
+		if !ok {
+			created, resp, err := svc.CreateNodePool(ctx, clusterID, spec)
+			if err != nil {
+				return result, resp, fmt.Errorf("creating node pool %s: %w", want.Name, err)
+			}
+			result.Created = append(result.Created, created)
+			continue
+		}
+		update, err := nodePoolUpdateForSpec(have, spec)
+		if err != nil {
+			return result, resp, fmt.Errorf("updating node pool %s: %w", want.Name, err)
+		}
+		if update != nil {
+			updated, resp, err := svc.UpdateNodePool(ctx, clusterID, have.ID, update)
+			if err != nil {
+				return result, resp, fmt.Errorf("updating node pool %s: %w", want.Name, err)
+			}
+			result.Updated = append(result.Updated, updated)
+		}
+	}
+
+	for _, have := range existing {
+		if wanted[have.Name] {
+			continue
+		}
+		if opts == nil || !opts.DeleteExtra {
+			result.Extra = append(result.Extra, have.Name)
+			continue
+		}
+		if resp, err := svc.DeleteNodePool(ctx, clusterID, have.ID); err != nil {
+			return result, resp, fmt.Errorf("deleting node pool %s: %w", have.Name, err)
+		}
+		result.Deleted = append(result.Deleted, have.Name)
+	}
+	return result, resp, nil
+}
+
+// findClusterByName pages through all clusters and returns the one with the
+// given name, or nil if there is none. It returns an error matching
+// ErrKubernetesMultipleClustersFound if several clusters share the name.
+func (svc *KubernetesServiceOp) findClusterByName(ctx context.Context, name string) (*KubernetesCluster, *Response, error) {
+	clusters, resp, err := svc.ListClustersAll(ctx, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var found *KubernetesCluster
+	for _, c := range clusters {
+		if c.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, resp, fmt.Errorf("%w with name %q", ErrKubernetesMultipleClustersFound, name)
+		}
+		found = c
+	}
+	return found, resp, nil
+}
+
+// clusterUpdateForSpec returns the update that brings the cluster's mutable
+// settings in line with spec, or nil if they already match. The update starts
+// from the cluster's current settings, built by ToUpdateRequest, so that the
+// settings that did not drift are not cleared by it. It returns an error
+// matching ErrKubernetesClearUnsupported if spec has no user tags but the
+// cluster has some, and one matching ErrKubernetesHADowngradeUnsupported if
+// spec turns off HA on a highly available cluster.
+func clusterUpdateForSpec(cluster *KubernetesCluster, spec *KubernetesClusterCreateRequest) (*KubernetesClusterUpdateRequest, error) {
+	update := cluster.ToUpdateRequest()
+	changed := false
+
+	if !stringSetsEqual(userTags(cluster.Tags), userTags(spec.Tags)) {
+		if len(userTags(spec.Tags)) == 0 {
+			return nil, fmt.Errorf("cluster tags: %w", ErrKubernetesClearUnsupported)
+		}
+		update.Tags = spec.Tags
+		changed = true
+	}
+	if spec.MaintenancePolicy != nil && !maintenancePoliciesEqual(cluster.MaintenancePolicy, spec.MaintenancePolicy) {
+		update.MaintenancePolicy = spec.MaintenancePolicy
+		changed = true
+	}
+	if cluster.AutoUpgrade != spec.AutoUpgrade {
+		update.AutoUpgrade = PtrTo(spec.AutoUpgrade)
+		changed = true
+	}
+	// SurgeUpgrade cannot be turned off through an update request, as false
+	// is omitted from it.
+	if spec.SurgeUpgrade && !cluster.SurgeUpgrade {
+		update.SurgeUpgrade = true
+		changed = true
+	}
+	if spec.HA != cluster.HA {
+		if !spec.HA {
+			return nil, fmt.Errorf("cluster %s: %w", cluster.ID, ErrKubernetesHADowngradeUnsupported)
+		}
+		update.HA = PtrTo(true)
+		changed = true
+	}
+	if spec.ControlPlaneFirewall != nil && !controlPlaneFirewallsEqual(cluster.ControlPlaneFirewall, spec.ControlPlaneFirewall) {
+		update.ControlPlaneFirewall = spec.ControlPlaneFirewall
+		changed = true
+	}
+	if spec.ClusterAutoscalerConfiguration != nil && !autoscalerConfigurationsEqual(cluster.ClusterAutoscalerConfiguration, spec.ClusterAutoscalerConfiguration) {
+		update.ClusterAutoscalerConfiguration = spec.ClusterAutoscalerConfiguration
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return update, nil
+}
+
+// EqualConfig reports whether sending req would leave the cluster's mutable
+// settings unchanged, so that no-op updates can be skipped. Only the settings
+// req changes are compared: the name, tags, maintenance policy, autoscaler
+// configuration, control plane firewall, auto and surge upgrades and HA.
+// Fields left empty or nil in req are treated as unchanged. Tags and firewall
+// addresses are compared as sets, and the k8s and k8s:* tags added by the API
+// are ignored.
+func (kc *KubernetesCluster) EqualConfig(req *KubernetesClusterUpdateRequest) bool {
+	if req == nil {
+		return true
+	}
+	if req.Name != "" && req.Name != kc.Name {
+		return false
+	}
+	if len(req.Tags) > 0 && !stringSetsEqual(userTags(kc.Tags), userTags(req.Tags)) {
+		return false
+	}
+	if req.MaintenancePolicy != nil && !maintenancePoliciesEqual(kc.MaintenancePolicy, req.MaintenancePolicy) {
+		return false
+	}
+	if req.ClusterAutoscalerConfiguration != nil && !autoscalerConfigurationsEqual(kc.ClusterAutoscalerConfiguration, req.ClusterAutoscalerConfiguration) {
+		return false
+	}
+	if req.ControlPlaneFirewall != nil && !controlPlaneFirewallsEqual(kc.ControlPlaneFirewall, req.ControlPlaneFirewall) {
+		return false
+	}
+	if req.AutoUpgrade != nil && *req.AutoUpgrade != kc.AutoUpgrade {
+		return false
+	}
+	// SurgeUpgrade cannot be turned off through an update request, as false
+	// is omitted from it.
+	if req.SurgeUpgrade && !kc.SurgeUpgrade {
+		return false
+	}
+	if req.HA != nil && *req.HA != kc.HA {
+		return false
+	}
+	return true
+}
+
+// nodePoolUpdateForSpec returns the update that brings the node pool in line
+// with spec, or nil if it already matches. It returns an error matching
+// ErrKubernetesClearUnsupported if spec has no labels or user tags but the
+// node pool has some.
+func nodePoolUpdateForSpec(pool *KubernetesNodePool, spec *KubernetesNodePoolCreateRequest) (*KubernetesNodePoolUpdateRequest, error) {
+	update := &KubernetesNodePoolUpdateRequest{Name: pool.Name}
+	changed := false
+
+	if spec.AutoScale != pool.AutoScale || (spec.AutoScale && (spec.MinNodes != pool.MinNodes || spec.MaxNodes != pool.MaxNodes)) {
+		update.AutoScale = PtrTo(spec.AutoScale)
+		update.MinNodes = PtrTo(spec.MinNodes)
+		update.MaxNodes = PtrTo(spec.MaxNodes)
+		changed = true
+	}
+	if !spec.AutoScale && spec.Count != pool.Count {
+		update.Count = PtrTo(spec.Count)
+		changed = true
+	}
+	if !stringMapsEqual(pool.Labels, spec.Labels) {
+		if len(spec.Labels) == 0 {
+			return nil, fmt.Errorf("labels: %w", ErrKubernetesClearUnsupported)
+		}
+		update.Labels = spec.Labels
+		changed = true
+	}
+	if !stringSetsEqual(userTags(pool.Tags), userTags(spec.Tags)) {
+		if len(userTags(spec.Tags)) == 0 {
+			return nil, fmt.Errorf("tags: %w", ErrKubernetesClearUnsupported)
+		}
+		update.Tags = spec.Tags
+		changed = true
+	}
+	if !taintSetsEqual(pool.Taints, spec.Taints) {
+		taints := spec.Taints
+		if taints == nil {
+			taints = []Taint{}
+		}
+		update.Taints = &taints
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return update, nil
+}
+
+// maintenancePoliciesEqual compares the start time and day of two maintenance
+// policies. The duration is only compared if want specifies one, as it is
+// usually chosen by the API.
+func maintenancePoliciesEqual(have, want *KubernetesMaintenancePolicy) bool {
+	if have == nil || want == nil {
+		return have == want
+	}
+	if have.StartTime != want.StartTime || have.Day != want.Day {
+		return false
+	}
+	return want.Duration == "" || have.Duration == want.Duration
+}
+
+// autoscalerConfigurationsEqual compares the settings want sets with those of
+// have.
+func autoscalerConfigurationsEqual(have, want *KubernetesClusterAutoscalerConfiguration) bool {
+	if have == nil {
+		have = &KubernetesClusterAutoscalerConfiguration{}
+	}
+	if want.ScaleDownUtilizationThreshold != nil && (have.ScaleDownUtilizationThreshold == nil || *have.ScaleDownUtilizationThreshold != *want.ScaleDownUtilizationThreshold) {
+		return false
+	}
+	if want.ScaleDownUnneededTime != nil && (have.ScaleDownUnneededTime == nil || *have.ScaleDownUnneededTime != *want.ScaleDownUnneededTime) {
+		return false
+	}
+	return true
+}
+
+// controlPlaneFirewallsEqual compares two control plane firewalls, treating
+// the allowed addresses as a set. A missing firewall is a disabled one without
+// addresses.
+func controlPlaneFirewallsEqual(have, want *KubernetesControlPlaneFirewall) bool {
+	if have == nil {
+		have = &KubernetesControlPlaneFirewall{}
+	}
+	if want.Enabled != nil && *want.Enabled != have.isEnabled() {
+		return false
+	}
+	return stringSetsEqual(have.AllowedAddresses, want.AllowedAddresses)
+}
+
+// userTags filters out the k8s and k8s:* tags that the API adds to clusters
+// and node pools on its own.
+func userTags(tags []string) []string {
+	var filtered []string
+	for _, tag := range tags {
+		if tag == "k8s" || strings.HasPrefix(tag, "k8s:") {
+			continue
+		}
+		filtered = append(filtered, tag)
+	}
+	return filtered
+}
+
+// stringSetsEqual reports whether a and b hold the same strings, ignoring
+// order and duplicates.
+func stringSetsEqual(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = false
+	}
+	for _, s := range b {
+		if _, ok := set[s]; !ok {
+			return false
+		}
+		set[s] = true
+	}
+	for _, seen := range set {
+		if !seen {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMapsEqual reports whether a and b hold the same entries. A nil map is
+// equal to an empty one.
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// taintSetsEqual reports whether a and b hold the same taints, ignoring order.
+func taintSetsEqual(a, b []Taint) bool {
+	as := make([]string, 0, len(a))
+	for _, t := range a {
+		as = append(as, t.String())
+	}
+	bs := make([]string, 0, len(b))
+	for _, t := range b {
+		bs = append(bs, t.String())
+	}
+	return stringSetsEqual(as, bs)
+}
@@ -0,0 +1,533 @@
+package godo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEnsureClusterJSON = `
+{
+	"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+	"name": "antoine",
+	"region": "nyc1",
+	"version": "1.30.2-do.0",
+	"tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod"],
+	"maintenance_policy": {"start_time": "04:00", "duration": "4h0m0s", "day": "sunday"},
+	"auto_upgrade": true,
+	"node_pools": [
+		{
+			"id": "pool-a-id",
+			"name": "pool-a",
+			"size": "s-1vcpu-2gb",
+			"count": 3,
+			"tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "k8s:worker"],
+			"labels": {"role": "web"}
+		}
+	]
+}`
+
+func testEnsureClusterSpec() *KubernetesClusterCreateRequest {
+	return &KubernetesClusterCreateRequest{
+		Name:        "antoine",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.30.2-do.0",
+		Tags:        []string{"env:prod"},
+		MaintenancePolicy: &KubernetesMaintenancePolicy{
+			StartTime: "04:00",
+			Day:       KubernetesMaintenanceDaySunday,
+		},
+		AutoUpgrade: true,
+		NodePools: []*KubernetesNodePoolCreateRequest{
+			{Name: "pool-a", Size: "s-1vcpu-2gb", Count: 3, Labels: map[string]string{"role": "web"}},
+		},
+	}
+}
+
+func TestKubernetesClusters_EnsureCluster_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"kubernetes_clusters": [{"id": "deadbeef-dead-4aa5-beef-deadbeef347d", "name": "other"}], "meta": {"total": 1}}`)
+		case http.MethodPost:
+			v := new(KubernetesClusterCreateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, "antoine", v.Name)
+			fmt.Fprintf(w, `{"kubernetes_cluster": %s}`, testEnsureClusterJSON)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, created, _, err := kubeSvc.EnsureCluster(ctx, testEnsureClusterSpec())
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", got.ID)
+}
+
+func TestKubernetesClusters_EnsureCluster_NoOp(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, testEnsureClusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	got, created, _, err := kubeSvc.EnsureCluster(ctx, testEnsureClusterSpec())
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", got.ID)
+}
+
+func TestKubernetesClusters_EnsureCluster_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	spec := testEnsureClusterSpec()
+	spec.Tags = append(spec.Tags, "team:platform")
+	spec.NodePools[0].Count = 5
+	spec.NodePools = append(spec.NodePools, &KubernetesNodePoolCreateRequest{Name: "pool-b", Size: "s-2vcpu-4gb", Count: 2})
+
+	var calls []string
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, testEnsureClusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" cluster")
+		if r.Method == http.MethodPut {
+			buf := new(bytes.Buffer)
+			buf.ReadFrom(r.Body)
+			assert.JSONEq(t, `{
+				"name": "antoine",
+				"tags": ["env:prod", "team:platform"],
+				"maintenance_policy": {"start_time": "04:00", "duration": "4h0m0s", "day": "sunday"},
+				"auto_upgrade": true,
+				"ha": false
+			}`, buf.String())
+		}
+		fmt.Fprintf(w, `{"kubernetes_cluster": %s}`, testEnsureClusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		calls = append(calls, "POST pool")
+		v := new(KubernetesNodePoolCreateRequest)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+		assert.Equal(t, spec.NodePools[1], v)
+		fmt.Fprint(w, `{"node_pool": {"id": "pool-b-id", "name": "pool-b"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/pool-a-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		calls = append(calls, "PUT pool")
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		assert.Equal(t, `{"name":"pool-a","count":5}`+"\n", buf.String())
+		fmt.Fprint(w, `{"node_pool": {"id": "pool-a-id", "name": "pool-a", "count": 5}}`)
+	})
+
+	_, created, _, err := kubeSvc.EnsureCluster(ctx, spec)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, []string{"PUT cluster", "PUT pool", "POST pool", "GET cluster"}, calls)
+}
+
+func TestKubernetesClusters_EnsureCluster_UpdateSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	spec := testEnsureClusterSpec()
+	spec.HA = true
+	spec.ControlPlaneFirewall = &KubernetesControlPlaneFirewall{Enabled: PtrTo(true), AllowedAddresses: []string{"10.0.0.0/8"}}
+	spec.ClusterAutoscalerConfiguration = &KubernetesClusterAutoscalerConfiguration{ScaleDownUtilizationThreshold: PtrTo(0.7)}
+
+	puts := 0
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, testEnsureClusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		puts++
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		assert.JSONEq(t, `{
+			"name": "antoine",
+			"tags": ["env:prod"],
+			"maintenance_policy": {"start_time": "04:00", "duration": "4h0m0s", "day": "sunday"},
+			"auto_upgrade": true,
+			"ha": true,
+			"control_plane_firewall": {"enabled": true, "allowed_addresses": ["10.0.0.0/8"]},
+			"cluster_autoscaler_configuration": {"scale_down_utilization_threshold": 0.7, "scale_down_unneeded_time": null}
+		}`, buf.String())
+		fmt.Fprintf(w, `{"kubernetes_cluster": %s}`, testEnsureClusterJSON)
+	})
+
+	_, _, _, err := kubeSvc.EnsureCluster(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+}
+
+func TestKubernetesClusters_EnsureCluster_HADowngrade(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	clusterJSON := strings.Replace(testEnsureClusterJSON, `"auto_upgrade": true,`, `"auto_upgrade": true, "ha": true,`, 1)
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, clusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, _, _, err := kubeSvc.EnsureCluster(ctx, testEnsureClusterSpec())
+	assert.ErrorIs(t, err, ErrKubernetesHADowngradeUnsupported)
+}
+
+func TestKubernetesClusters_EnsureCluster_DuplicateName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s, %s], "meta": {"total": 2}}`, testEnsureClusterJSON, testEnsureClusterJSON)
+	})
+
+	_, _, _, err := kubeSvc.EnsureCluster(ctx, testEnsureClusterSpec())
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_EnsureCluster_Labels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	spec := testEnsureClusterSpec()
+	spec.NodePools[0].Labels = map[string]string{"role": "api"}
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, testEnsureClusterJSON)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{"kubernetes_cluster": %s}`, testEnsureClusterJSON)
+	})
+	var body string
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/pool-a-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		body = buf.String()
+		fmt.Fprint(w, `{"node_pool": {"id": "pool-a-id", "name": "pool-a", "count": 3, "labels": {"role": "api"}}}`)
+	})
+
+	_, _, _, err := kubeSvc.EnsureCluster(ctx, spec)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"pool-a","labels":{"role":"api"}}`+"\n", body)
+}
+
+func TestKubernetesClusters_EnsureCluster_ClearUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(*KubernetesClusterCreateRequest)
+	}{
+		{
+			name: "cluster tags",
+			edit: func(spec *KubernetesClusterCreateRequest) { spec.Tags = nil },
+		},
+		{
+			name: "node pool labels",
+			edit: func(spec *KubernetesClusterCreateRequest) { spec.NodePools[0].Labels = map[string]string{} },
+		},
+		{
+			name: "node pool tags",
+			edit: func(spec *KubernetesClusterCreateRequest) { spec.NodePools[0].Tags = []string{"k8s"} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			kubeSvc := client.Kubernetes
+
+			clusterJSON := strings.Replace(testEnsureClusterJSON, `"k8s:worker"`, `"k8s:worker", "team:web"`, 1)
+			mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				fmt.Fprintf(w, `{"kubernetes_clusters": [%s], "meta": {"total": 1}}`, clusterJSON)
+			})
+			mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/", func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+			})
+
+			spec := testEnsureClusterSpec()
+			spec.NodePools[0].Tags = []string{"team:web"}
+			tt.edit(spec)
+
+			_, _, _, err := kubeSvc.EnsureCluster(ctx, spec)
+			assert.ErrorIs(t, err, ErrKubernetesClearUnsupported)
+		})
+	}
+}
+
+func TestKubernetesClusters_EnsureCluster_NilNodePool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	spec := testEnsureClusterSpec()
+	spec.NodePools = append(spec.NodePools, nil)
+
+	_, _, _, err := kubeSvc.EnsureCluster(ctx, spec)
+	var argErr *ArgError
+	require.ErrorAs(t, err, &argErr)
+	assert.Equal(t, "NodePools[1]", argErr.arg)
+}
+
+func testReconcileNodePoolsHandlers(t *testing.T, deleted *[]string) {
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pools": [
+				{"id": "pool-a-id", "name": "pool-a", "size": "s-1vcpu-2gb", "count": 3, "labels": {"role": "web"}},
+				{"id": "pool-b-id", "name": "pool-b", "size": "s-1vcpu-2gb", "count": 2},
+				{"id": "pool-old-id", "name": "pool-old", "size": "s-1vcpu-2gb", "count": 1}
+			]}`)
+		case http.MethodPost:
+			v := new(KubernetesNodePoolCreateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, &KubernetesNodePoolCreateRequest{Name: "pool-c", Size: "c-4", Count: 1}, v)
+			fmt.Fprint(w, `{"node_pool": {"id": "pool-c-id", "name": "pool-c", "size": "c-4", "count": 1}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/pool-b-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		v := new(KubernetesNodePoolUpdateRequest)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+		assert.Equal(t, PtrTo(5), v.Count)
+		fmt.Fprint(w, `{"node_pool": {"id": "pool-b-id", "name": "pool-b", "size": "s-1vcpu-2gb", "count": 5}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/pool-old-id", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		*deleted = append(*deleted, "pool-old-id")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func testReconcileNodePoolsDesired() []*KubernetesNodePool {
+	return []*KubernetesNodePool{
+		{Name: "pool-a", Size: "s-1vcpu-2gb", Count: 3, Labels: map[string]string{"role": "web"}},
+		{Name: "pool-b", Size: "s-1vcpu-2gb", Count: 5},
+		{Name: "pool-c", Size: "c-4", Count: 1},
+	}
+}
+
+func TestKubernetesClusters_ReconcileNodePools(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var deleted []string
+	testReconcileNodePoolsHandlers(t, &deleted)
+
+	got, _, err := kubeSvc.ReconcileNodePools(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", testReconcileNodePoolsDesired())
+	require.NoError(t, err)
+	require.Len(t, got.Created, 1)
+	assert.Equal(t, "pool-c-id", got.Created[0].ID)
+	require.Len(t, got.Updated, 1)
+	assert.Equal(t, 5, got.Updated[0].Count)
+	assert.Empty(t, got.Deleted)
+	assert.Equal(t, []string{"pool-old"}, got.Extra)
+	assert.Empty(t, deleted)
+}
+
+func TestKubernetesClusters_ReconcileNodePools_DeleteExtra(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var deleted []string
+	testReconcileNodePoolsHandlers(t, &deleted)
+
+	got, _, err := kubeSvc.ReconcileNodePoolsWithOptions(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", testReconcileNodePoolsDesired(), &NodePoolReconcileOptions{DeleteExtra: true})
+	require.NoError(t, err)
+	assert.Len(t, got.Created, 1)
+	assert.Len(t, got.Updated, 1)
+	assert.Equal(t, []string{"pool-old"}, got.Deleted)
+	assert.Empty(t, got.Extra)
+	assert.Equal(t, []string{"pool-old-id"}, deleted)
+}
+
+func TestKubernetesClusters_ReconcileNodePools_ClearUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		pool string
+	}{
+		{
+			name: "labels",
+			pool: `{"id": "pool-a-id", "name": "pool-a", "size": "s-1vcpu-2gb", "count": 3, "labels": {"role": "web"}}`,
+		},
+		{
+			name: "tags",
+			pool: `{"id": "pool-a-id", "name": "pool-a", "size": "s-1vcpu-2gb", "count": 3, "tags": ["k8s", "team:web"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			kubeSvc := client.Kubernetes
+
+			mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				fmt.Fprintf(w, `{"node_pools": [%s]}`, tt.pool)
+			})
+			mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/pool-a-id", func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+			})
+
+			got, _, err := kubeSvc.ReconcileNodePools(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", []*KubernetesNodePool{
+				{Name: "pool-a", Size: "s-1vcpu-2gb", Count: 3},
+			})
+			assert.ErrorIs(t, err, ErrKubernetesClearUnsupported)
+			assert.Empty(t, got.Updated)
+		})
+	}
+}
+
+func TestKubernetesClusters_ReconcileNodePools_DuplicateName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	_, _, err := kubeSvc.ReconcileNodePools(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", []*KubernetesNodePool{
+		{Name: "pool-a", Count: 1},
+		{Name: "pool-a", Count: 2},
+	})
+	require.Equal(t, NewArgError("desired[1].Name", `"pool-a" is not unique`), err)
+}
+
+func TestKubernetesCluster_EqualConfig(t *testing.T) {
+	cluster := &KubernetesCluster{
+		Name:         "prod",
+		Tags:         []string{"k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod", "team:platform"},
+		AutoUpgrade:  true,
+		SurgeUpgrade: true,
+		HA:           false,
+		MaintenancePolicy: &KubernetesMaintenancePolicy{
+			StartTime: "04:00",
+			Duration:  "4h0m0s",
+			Day:       KubernetesMaintenanceDaySunday,
+		},
+		ControlPlaneFirewall: &KubernetesControlPlaneFirewall{
+			Enabled:          PtrTo(true),
+			AllowedAddresses: []string{"1.2.3.4/32", "10.0.0.0/8"},
+		},
+		ClusterAutoscalerConfiguration: &KubernetesClusterAutoscalerConfiguration{
+			ScaleDownUtilizationThreshold: PtrTo(0.5),
+			ScaleDownUnneededTime:         PtrTo("1m"),
+		},
+	}
+
+	tests := []struct {
+		name string
+		req  *KubernetesClusterUpdateRequest
+		want bool
+	}{
+		{name: "nil", want: true},
+		{name: "empty", req: &KubernetesClusterUpdateRequest{}, want: true},
+		{name: "round trip", req: cluster.ToUpdateRequest(), want: true},
+		{
+			name: "tags reordered",
+			req:  &KubernetesClusterUpdateRequest{Tags: []string{"team:platform", "env:prod"}},
+			want: true,
+		},
+		{
+			name: "firewall addresses reordered",
+			req: &KubernetesClusterUpdateRequest{ControlPlaneFirewall: &KubernetesControlPlaneFirewall{
+				Enabled:          PtrTo(true),
+				AllowedAddresses: []string{"10.0.0.0/8", "1.2.3.4/32"},
+			}},
+			want: true,
+		},
+		{
+			name: "maintenance policy without duration",
+			req: &KubernetesClusterUpdateRequest{MaintenancePolicy: &KubernetesMaintenancePolicy{
+				StartTime: "04:00",
+				Day:       KubernetesMaintenanceDaySunday,
+			}},
+			want: true,
+		},
+		{name: "name changed", req: &KubernetesClusterUpdateRequest{Name: "staging"}, want: false},
+		{name: "tag added", req: &KubernetesClusterUpdateRequest{Tags: []string{"env:prod", "team:platform", "new"}}, want: false},
+		{
+			name: "maintenance day changed",
+			req: &KubernetesClusterUpdateRequest{MaintenancePolicy: &KubernetesMaintenancePolicy{
+				StartTime: "04:00",
+				Day:       KubernetesMaintenanceDayMonday,
+			}},
+			want: false,
+		},
+		{
+			name: "autoscaler changed",
+			req: &KubernetesClusterUpdateRequest{ClusterAutoscalerConfiguration: &KubernetesClusterAutoscalerConfiguration{
+				ScaleDownUnneededTime: PtrTo("5m"),
+			}},
+			want: false,
+		},
+		{
+			name: "firewall disabled",
+			req: &KubernetesClusterUpdateRequest{ControlPlaneFirewall: &KubernetesControlPlaneFirewall{
+				Enabled:          PtrTo(false),
+				AllowedAddresses: []string{"1.2.3.4/32", "10.0.0.0/8"},
+			}},
+			want: false,
+		},
+		{name: "auto upgrade changed", req: &KubernetesClusterUpdateRequest{AutoUpgrade: PtrTo(false)}, want: false},
+		{name: "HA changed", req: &KubernetesClusterUpdateRequest{HA: PtrTo(true)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cluster.EqualConfig(tt.req))
+		})
+	}
+}
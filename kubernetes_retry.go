@@ -0,0 +1,74 @@
+package godo
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultKubernetesReadRetryMaxWait = 30 * time.Second
+	defaultKubernetesReadRetryWait    = time.Second
+)
+
+// KubernetesReadRetryConfig configures retries of Kubernetes read requests
+// that are rate limited. Only idempotent GET requests of the Kubernetes
+// service are retried: Get, List, ListNodePools, GetNodePool and GetOptions.
+// Mutating requests are never retried.
+//
+// These retries are independent of RetryConfig, which applies to all requests
+// of the client.
+type KubernetesReadRetryConfig struct {
+	// MaxRetries is the number of times a rate limited request is retried.
+	// Zero, the default, disables retries.
+	MaxRetries int
+
+	// MaxWait caps the time waited before a retry. Defaults to 30 seconds.
+	MaxWait time.Duration
+}
+
+// WithKubernetesReadRetries enables retrying Kubernetes read requests that
+// fail with 429 Too Many Requests. See KubernetesReadRetryConfig.
+func WithKubernetesReadRetries(config KubernetesReadRetryConfig) ClientOpt {
+	return func(c *Client) error {
+		c.KubernetesReadRetryConfig = config
+		return nil
+	}
+}
+
+// doRead sends a read request, retrying it as configured by the client's
+// KubernetesReadRetryConfig when it is rate limited. It must only be used for
+// requests without a body.
+func (svc *KubernetesServiceOp) doRead(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+	config := svc.client.KubernetesReadRetryConfig
+	for attempt := 0; ; attempt++ {
+		resp, err := svc.client.Do(ctx, req, v)
+		if err == nil || attempt >= config.MaxRetries || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		if err := sleepContext(ctx, config.retryWait(resp, time.Now())); err != nil {
+			return resp, err
+		}
+	}
+}
+
+// retryWait returns how long to wait before retrying a rate limited request.
+// The Retry-After header is preferred over the reset time of the rate limit.
+func (c KubernetesReadRetryConfig) retryWait(resp *Response, now time.Time) time.Duration {
+	maxWait := c.MaxWait
+	if maxWait <= 0 {
+		maxWait = defaultKubernetesReadRetryMaxWait
+	}
+
+	wait := defaultKubernetesReadRetryWait
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if reset := resp.Rate.Reset.Time; !reset.IsZero() && reset.After(now) {
+		wait = reset.Sub(now)
+	}
+	if wait > maxWait {
+		wait = maxWait
+	}
+	return wait
+}
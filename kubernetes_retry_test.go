@@ -0,0 +1,123 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesReadRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.KubernetesReadRetryConfig = KubernetesReadRetryConfig{MaxRetries: 2}
+	kubeSvc := client.Kubernetes
+
+	requests := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"id": "too_many_requests", "message": "API Rate limit exceeded."}`)
+			return
+		}
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "deadbeef-dead-4aa5-beef-deadbeef347d"}}`)
+	})
+
+	got, resp, err := kubeSvc.Get(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.NoError(t, err)
+	assert.Equal(t, "deadbeef-dead-4aa5-beef-deadbeef347d", got.ID)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, requests)
+}
+
+func TestKubernetesReadRetries_GivesUp(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.KubernetesReadRetryConfig = KubernetesReadRetryConfig{MaxRetries: 2}
+	kubeSvc := client.Kubernetes
+
+	requests := 0
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, resp, err := kubeSvc.List(ctx, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 3, requests)
+}
+
+func TestKubernetesReadRetries_Disabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	requests := 0
+	mux.HandleFunc("/v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, _, err := kubeSvc.GetOptions(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestKubernetesReadRetries_MutatingCallsNotRetried(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.KubernetesReadRetryConfig = KubernetesReadRetryConfig{MaxRetries: 2}
+	kubeSvc := client.Kubernetes
+
+	requests := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, _, err := kubeSvc.Update(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", &KubernetesClusterUpdateRequest{Name: "antoine"})
+	require.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestKubernetesReadRetryConfig_RetryWait(t *testing.T) {
+	now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	response := func(retryAfter string, reset time.Time) *Response {
+		r := &Response{Response: &http.Response{Header: http.Header{}}}
+		if retryAfter != "" {
+			r.Header.Set("Retry-After", retryAfter)
+		}
+		r.Rate.Reset = Timestamp{reset}
+		return r
+	}
+
+	config := KubernetesReadRetryConfig{}
+	assert.Equal(t, 5*time.Second, config.retryWait(response("5", now.Add(time.Minute)), now))
+	assert.Equal(t, 12*time.Second, config.retryWait(response("", now.Add(12*time.Second)), now))
+	assert.Equal(t, time.Second, config.retryWait(response("", time.Time{}), now))
+	assert.Equal(t, 30*time.Second, config.retryWait(response("3600", time.Time{}), now))
+
+	config.MaxWait = 2 * time.Second
+	assert.Equal(t, 2*time.Second, config.retryWait(response("5", time.Time{}), now))
+}
+
+func TestWithKubernetesReadRetries(t *testing.T) {
+	c, err := New(nil, WithKubernetesReadRetries(KubernetesReadRetryConfig{MaxRetries: 3, MaxWait: time.Second}))
+	require.NoError(t, err)
+	assert.Equal(t, KubernetesReadRetryConfig{MaxRetries: 3, MaxWait: time.Second}, c.KubernetesReadRetryConfig)
+}
@@ -0,0 +1,211 @@
+package godo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// kubernetesNodePoolTemplateConcurrency bounds the number of node pool
+// templates GetNodePoolTemplates fetches at once.
+const kubernetesNodePoolTemplateConcurrency = 4
+
+// KubernetesNodePoolTemplate describes the nodes of a node pool, for use by
+// the cluster autoscaler when simulating scale-ups.
+type KubernetesNodePoolTemplate struct {
+	Template *KubernetesNodeTemplate `json:"template,omitempty"`
+	MaxNodes uint32                  `json:"max_nodes,omitempty"`
+	MinNodes uint32                  `json:"min_nodes,omitempty"`
+}
+
+// KubernetesNodeTemplate is the template of the nodes of a node pool.
+type KubernetesNodeTemplate struct {
+	ClusterUUID string                       `json:"cluster_uuid,omitempty"`
+	Name        string                       `json:"name,omitempty"`
+	Slug        string                       `json:"slug,omitempty"`
+	Labels      map[string]string            `json:"labels,omitempty"`
+	Taints      []string                     `json:"taints,omitempty"`
+	Capacity    *KubernetesNodePoolResources `json:"capacity,omitempty"`
+	Allocatable *KubernetesNodePoolResources `json:"allocatable,omitempty"`
+}
+
+// KubernetesNodePoolResources are the resources of a node.
+type KubernetesNodePoolResources struct {
+	CPU    int64  `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+	Pods   int64  `json:"pods,omitempty"`
+}
+
+// SystemReserved returns the resources each node of the template reserves for
+// the system, i.e. its capacity minus its allocatable resources. Memory is
+// returned in the largest binary unit that represents it exactly, e.g. "512Mi".
+func (t *KubernetesNodeTemplate) SystemReserved() (*KubernetesNodePoolResources, error) {
+	if t == nil || t.Capacity == nil || t.Allocatable == nil {
+		return nil, errors.New("node template has no capacity or allocatable resources")
+	}
+
+	reserved := &KubernetesNodePoolResources{
+		CPU:  t.Capacity.CPU - t.Allocatable.CPU,
+		Pods: t.Capacity.Pods - t.Allocatable.Pods,
+	}
+	if t.Capacity.Memory != "" || t.Allocatable.Memory != "" {
+		capacity, err := parseKubernetesMemory(t.Capacity.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("parsing memory capacity: %w", err)
+		}
+		allocatable, err := parseKubernetesMemory(t.Allocatable.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("parsing allocatable memory: %w", err)
+		}
+		reserved.Memory = formatKubernetesMemory(capacity - allocatable)
+	}
+	return reserved, nil
+}
+
+// ClusterAllocatableResources returns the resources that can be scheduled on
+// the cluster: the allocatable resources of each node pool's template,
+// multiplied by the node pool's node count and summed across node pools.
+// Memory is returned like in SystemReserved.
+func (svc *KubernetesServiceOp) ClusterAllocatableResources(ctx context.Context, clusterID string) (*KubernetesNodePoolResources, *Response, error) {
+	pools, resp, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var (
+		total  KubernetesNodePoolResources
+		memory int64
+	)
+	for _, pool := range pools {
+		if pool.Count == 0 {
+			continue
+		}
+		template, resp, err := svc.GetNodePoolTemplate(ctx, clusterID, pool.Name)
+		if err != nil {
+			return nil, resp, fmt.Errorf("getting template of node pool %s: %w", pool.Name, err)
+		}
+		if template.Template == nil || template.Template.Allocatable == nil {
+			return nil, resp, fmt.Errorf("template of node pool %s has no allocatable resources", pool.Name)
+		}
+		allocatable := template.Template.Allocatable
+		nodeMemory, err := parseKubernetesMemory(allocatable.Memory)
+		if err != nil {
+			return nil, resp, fmt.Errorf("parsing allocatable memory of node pool %s: %w", pool.Name, err)
+		}
+
+		count := int64(pool.Count)
+		total.CPU += count * allocatable.CPU
+		total.Pods += count * allocatable.Pods
+		memory += count * nodeMemory
+	}
+	total.Memory = formatKubernetesMemory(memory)
+	return &total, resp, nil
+}
+
+// kubernetesMemoryUnits are the suffixes of Kubernetes memory quantities, with
+// the binary units first so that "Mi" is not mistaken for "M".
+var kubernetesMemoryUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"Ei", 1 << 60},
+	{"Pi", 1 << 50},
+	{"Ti", 1 << 40},
+	{"Gi", 1 << 30},
+	{"Mi", 1 << 20},
+	{"Ki", 1 << 10},
+	{"E", 1e18},
+	{"P", 1e15},
+	{"T", 1e12},
+	{"G", 1e9},
+	{"M", 1e6},
+	{"k", 1e3},
+}
+
+// parseKubernetesMemory parses an integer Kubernetes memory quantity such as
+// "2048Mi" or "4G" into bytes. An empty string is zero.
+func parseKubernetesMemory(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	number := s
+	for _, unit := range kubernetesMemoryUnits {
+		if n, ok := strings.CutSuffix(s, unit.suffix); ok {
+			number, multiplier = n, unit.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q", s)
+	}
+	return n * multiplier, nil
+}
+
+// formatKubernetesMemory formats bytes in the largest binary unit that
+// represents them exactly.
+func formatKubernetesMemory(bytes int64) string {
+	if bytes == 0 {
+		return "0"
+	}
+	for _, unit := range kubernetesMemoryUnits[:6] {
+		if bytes%unit.bytes == 0 {
+			return strconv.FormatInt(bytes/unit.bytes, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// GetNodePoolTemplate retrieves the template of the node pool with the given
+// name.
+func (svc *KubernetesServiceOp) GetNodePoolTemplate(ctx context.Context, clusterID string, nodePoolName string) (*KubernetesNodePoolTemplate, *Response, error) {
+	path := fmt.Sprintf("%s/%s/node_pools_template/%s", kubernetesClustersPath, clusterID, nodePoolName)
+	req, err := svc.client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	root := new(KubernetesNodePoolTemplate)
+	resp, err := svc.client.Do(ctx, req, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	return root, resp, nil
+}
+
+// GetNodePoolTemplates retrieves the templates of all node pools of a
+// cluster, keyed by node pool name. The templates are fetched concurrently.
+// Failures for individual node pools are joined into the returned error,
+// while the templates of the other node pools are still returned.
+func (svc *KubernetesServiceOp) GetNodePoolTemplates(ctx context.Context, clusterID string) (map[string]*KubernetesNodePoolTemplate, error) {
+	pools, _, err := svc.ListNodePoolsAll(ctx, clusterID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(pools))
+	for i, pool := range pools {
+		names[i] = pool.Name
+	}
+
+	var (
+		mu        sync.Mutex
+		templates = make(map[string]*KubernetesNodePoolTemplate, len(pools))
+	)
+	errs := runFleet(ctx, defaultFleetClock, names, kubernetesNodePoolTemplateConcurrency, func(ctx context.Context, name string) (*Response, error) {
+		template, resp, err := svc.GetNodePoolTemplate(ctx, clusterID, name)
+		if err != nil {
+			return resp, fmt.Errorf("getting template of node pool %s: %w", name, err)
+		}
+		mu.Lock()
+		templates[name] = template
+		mu.Unlock()
+		return resp, nil
+	})
+
+	return templates, errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClusters_GetNodePoolTemplate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools_template/pool-a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"template": {
+				"cluster_uuid": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+				"name": "pool-a",
+				"slug": "s-1vcpu-2gb",
+				"labels": {"pool": "a"},
+				"taints": ["key=value:NoSchedule"],
+				"capacity": {"cpu": 1, "memory": "2048Mi", "pods": 110},
+				"allocatable": {"cpu": 900, "memory": "1500Mi", "pods": 110}
+			},
+			"max_nodes": 5,
+			"min_nodes": 1
+		}`)
+	})
+
+	got, _, err := kubeSvc.GetNodePoolTemplate(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "pool-a")
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesNodePoolTemplate{
+		Template: &KubernetesNodeTemplate{
+			ClusterUUID: "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+			Name:        "pool-a",
+			Slug:        "s-1vcpu-2gb",
+			Labels:      map[string]string{"pool": "a"},
+			Taints:      []string{"key=value:NoSchedule"},
+			Capacity:    &KubernetesNodePoolResources{CPU: 1, Memory: "2048Mi", Pods: 110},
+			Allocatable: &KubernetesNodePoolResources{CPU: 900, Memory: "1500Mi", Pods: 110},
+		},
+		MaxNodes: 5,
+		MinNodes: 1,
+	}, got)
+}
+
+func TestKubernetesClusters_GetNodePoolTemplates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [{"id": "1", "name": "pool-a"}, {"id": "2", "name": "pool-b"}, {"id": "3", "name": "pool-c"}]}`)
+	})
+	for _, name := range []string{"pool-a", "pool-b", "pool-c"} {
+		name := name
+		mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools_template/"+name, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			if name == "pool-b" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"template": {"name": %q}}`, name)
+		})
+	}
+
+	got, err := kubeSvc.GetNodePoolTemplates(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "node pool pool-b")
+	require.Len(t, got, 2)
+	assert.Equal(t, "pool-a", got["pool-a"].Template.Name)
+	assert.Equal(t, "pool-c", got["pool-c"].Template.Name)
+}
+
+func TestKubernetesNodeTemplate_SystemReserved(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *KubernetesNodeTemplate
+		want     *KubernetesNodePoolResources
+		wantErr  bool
+	}{
+		{
+			name: "binary memory",
+			template: &KubernetesNodeTemplate{
+				Capacity:    &KubernetesNodePoolResources{CPU: 2, Memory: "4096Mi", Pods: 110},
+				Allocatable: &KubernetesNodePoolResources{CPU: 1, Memory: "3584Mi", Pods: 100},
+			},
+			want: &KubernetesNodePoolResources{CPU: 1, Memory: "512Mi", Pods: 10},
+		},
+		{
+			name: "mixed units",
+			template: &KubernetesNodeTemplate{
+				Capacity:    &KubernetesNodePoolResources{CPU: 4, Memory: "8Gi", Pods: 110},
+				Allocatable: &KubernetesNodePoolResources{CPU: 4, Memory: "7168Mi", Pods: 110},
+			},
+			want: &KubernetesNodePoolResources{CPU: 0, Memory: "1Gi", Pods: 0},
+		},
+		{
+			name: "no memory",
+			template: &KubernetesNodeTemplate{
+				Capacity:    &KubernetesNodePoolResources{CPU: 2, Pods: 110},
+				Allocatable: &KubernetesNodePoolResources{CPU: 1, Pods: 110},
+			},
+			want: &KubernetesNodePoolResources{CPU: 1},
+		},
+		{
+			name: "invalid memory",
+			template: &KubernetesNodeTemplate{
+				Capacity:    &KubernetesNodePoolResources{Memory: "lots"},
+				Allocatable: &KubernetesNodePoolResources{Memory: "1Gi"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "nil capacity",
+			template: &KubernetesNodeTemplate{Allocatable: &KubernetesNodePoolResources{CPU: 1}},
+			wantErr:  true,
+		},
+		{
+			name:     "nil allocatable",
+			template: &KubernetesNodeTemplate{Capacity: &KubernetesNodePoolResources{CPU: 1}},
+			wantErr:  true,
+		},
+		{
+			name:    "nil template",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.template.SystemReserved()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesClusters_ClusterAllocatableResources(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [
+			{"id": "1", "name": "pool-a", "count": 3},
+			{"id": "2", "name": "pool-b", "count": 2},
+			{"id": "3", "name": "pool-c", "count": 0}
+		]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools_template/pool-a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"template": {"name": "pool-a", "allocatable": {"cpu": 1, "memory": "1536Mi", "pods": 110}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools_template/pool-b", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"template": {"name": "pool-b", "allocatable": {"cpu": 4, "memory": "7Gi", "pods": 110}}}`)
+	})
+
+	got, _, err := kubeSvc.ClusterAllocatableResources(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesNodePoolResources{
+		CPU:    3*1 + 2*4,
+		Memory: "18944Mi",
+		Pods:   5 * 110,
+	}, got)
+}
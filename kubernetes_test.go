@@ -2,9 +2,14 @@ package godo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -246,6 +251,126 @@ func TestKubernetesClusters_ListClusters(t *testing.T) {
 	assert.Equal(t, wantRespMeta, gotRespMeta)
 }
 
+func TestKubernetesClusters_ListClustersAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "200", r.URL.Query().Get("per_page"))
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [{"id": "cluster-1"}, {"id": "cluster-2"}],
+				"links": {"pages": {"next": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2", "last": "https://api.digitalocean.com/v2/kubernetes/clusters?page=2"}},
+				"meta": {"total": 3}
+			}`)
+		case "2":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [{"id": "cluster-3"}],
+				"links": {"pages": {"first": "https://api.digitalocean.com/v2/kubernetes/clusters?page=1", "prev": "https://api.digitalocean.com/v2/kubernetes/clusters?page=1"}},
+				"meta": {"total": 3}
+			}`)
+		default:
+			t.Fatalf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	got, resp, err := kubeSvc.ListClustersAll(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for i, c := range got {
+		assert.Equal(t, fmt.Sprintf("cluster-%d", i+1), c.ID)
+	}
+	assert.True(t, resp.Links.IsLastPage())
+}
+
+func TestKubernetesClusters_ListClustersAll_PerPage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "25", r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, `{"kubernetes_clusters": [{"id": "cluster-1"}]}`)
+	})
+
+	opts := &ListOptions{PerPage: 25}
+	_, _, err := kubeSvc.ListClustersAll(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, &ListOptions{PerPage: 25}, opts)
+}
+
+func TestKubernetesClusters_ListUnhealthyClusters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"kubernetes_clusters": [
+				{"id": "cluster-1", "status": {"state": "running"}},
+				{"id": "cluster-2", "status": {"state": "degraded"}},
+				{"id": "cluster-3", "status": {"state": "provisioning"}},
+				{"id": "cluster-4", "status": {"state": "error", "message": "out of capacity"}},
+				{"id": "cluster-5"}
+			],
+			"meta": {"total": 5}
+		}`)
+	})
+
+	got, _, err := kubeSvc.ListUnhealthyClusters(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cluster-2", got[0].ID)
+	assert.Equal(t, "cluster-4", got[1].ID)
+}
+
+func TestKubernetesClusters_ListClustersByTag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [
+					{"id": "cluster-1", "tags": ["k8s", "env:prod"]},
+					{"id": "cluster-2", "tags": ["env:staging"]},
+					{"id": "cluster-3", "tags": ["ENV:PROD"]}
+				],
+				"links": {"pages": {"next": "http://example.com/v2/kubernetes/clusters?page=2", "last": "http://example.com/v2/kubernetes/clusters?page=2"}}
+			}`)
+		case "2":
+			fmt.Fprint(w, `{
+				"kubernetes_clusters": [
+					{"id": "cluster-4", "tags": ["env:production"]},
+					{"id": "cluster-5"},
+					{"id": "cluster-6", "tags": ["team:payments", "env:prod"]}
+				]
+			}`)
+		}
+	})
+
+	got, _, err := kubeSvc.ListClustersByTag(ctx, "env:prod", nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "cluster-1", got[0].ID)
+	assert.Equal(t, "cluster-6", got[1].ID)
+
+	_, _, err = kubeSvc.ListClustersByTag(ctx, "", nil)
+	require.Equal(t, NewArgError("tag", "cannot be empty"), err)
+}
+
 func TestKubernetesClusters_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -367,6 +492,124 @@ func TestKubernetesClusters_Get(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusters_GetClusterByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantID  string
+		wantErr error
+	}{
+		{
+			name:    "none",
+			body:    `{"kubernetes_clusters": [{"id": "1", "name": "staging"}]}`,
+			wantErr: ErrKubernetesClusterNotFound,
+		},
+		{
+			name:   "one",
+			body:   `{"kubernetes_clusters": [{"id": "1", "name": "staging"}, {"id": "2", "name": "prod"}]}`,
+			wantID: "2",
+		},
+		{
+			name:    "many",
+			body:    `{"kubernetes_clusters": [{"id": "1", "name": "prod"}, {"id": "2", "name": "prod"}]}`,
+			wantErr: ErrKubernetesMultipleClustersFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			kubeSvc := client.Kubernetes
+
+			mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				fmt.Fprint(w, tt.body)
+			})
+
+			got, _, err := kubeSvc.GetClusterByName(ctx, "prod")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Contains(t, err.Error(), `"prod"`)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, got.ID)
+		})
+	}
+}
+
+func TestKubernetesClusters_NotFound(t *testing.T) {
+	const clusterID = "deadbeef-dead-4aa5-beef-deadbeef347d"
+	tests := []struct {
+		name string
+		call func(KubernetesService) error
+	}{
+		{name: "Get", call: func(s KubernetesService) error { _, _, err := s.Get(ctx, clusterID); return err }},
+		{name: "GetUser", call: func(s KubernetesService) error { _, _, err := s.GetUser(ctx, clusterID); return err }},
+		{name: "GetUpgrades", call: func(s KubernetesService) error { _, _, err := s.GetUpgrades(ctx, clusterID); return err }},
+		{name: "GetKubeConfig", call: func(s KubernetesService) error { _, _, err := s.GetKubeConfig(ctx, clusterID); return err }},
+		{name: "GetCredentials", call: func(s KubernetesService) error {
+			_, _, err := s.GetCredentials(ctx, clusterID, &KubernetesClusterCredentialsGetRequest{})
+			return err
+		}},
+		{name: "Update", call: func(s KubernetesService) error {
+			_, _, err := s.Update(ctx, clusterID, &KubernetesClusterUpdateRequest{Name: "antoine"})
+			return err
+		}},
+		{name: "Upgrade", call: func(s KubernetesService) error {
+			_, err := s.Upgrade(ctx, clusterID, &KubernetesClusterUpgradeRequest{VersionSlug: "1.31.1-do.0"})
+			return err
+		}},
+		{name: "Delete", call: func(s KubernetesService) error { _, err := s.Delete(ctx, clusterID); return err }},
+		{name: "DeleteDangerous", call: func(s KubernetesService) error { _, err := s.DeleteDangerous(ctx, clusterID); return err }},
+		{name: "ListAssociatedResourcesForDeletion", call: func(s KubernetesService) error {
+			_, _, err := s.ListAssociatedResourcesForDeletion(ctx, clusterID)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			mux.HandleFunc("/v2/kubernetes/clusters/"+clusterID+"/", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"id": "not_found", "message": "The resource you were accessing could not be found."}`)
+			})
+			mux.HandleFunc("/v2/kubernetes/clusters/"+clusterID, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"id": "not_found", "message": "The resource you were accessing could not be found."}`)
+			})
+
+			err := tt.call(client.Kubernetes)
+			require.ErrorIs(t, err, ErrKubernetesClusterNotFound)
+
+			var notFound *KubernetesClusterNotFoundError
+			require.ErrorAs(t, err, &notFound)
+			assert.Equal(t, clusterID, notFound.ClusterID)
+
+			var errResp *ErrorResponse
+			require.ErrorAs(t, err, &errResp)
+			assert.Equal(t, http.StatusNotFound, errResp.Response.StatusCode)
+		})
+	}
+}
+
+func TestKubernetesClusters_Get_OtherErrorsNotWrapped(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, _, err := client.Kubernetes.Get(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrKubernetesClusterNotFound)
+}
+
 func TestKubernetesCluster_ToURN(t *testing.T) {
 	cluster := &KubernetesCluster{
 		ID: "deadbeef-dead-4aa5-beef-deadbeef347d",
@@ -441,6 +684,35 @@ func TestKubernetesClusters_GetKubeConfigWithExpiry(t *testing.T) {
 	require.Equal(t, blob, got.KubeconfigYAML)
 }
 
+func TestKubernetesClusters_GetKubeConfigWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+	want := "some YAML"
+	blob := []byte(want)
+	var gotQuery string
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		gotQuery = r.URL.RawQuery
+		fmt.Fprint(w, want)
+	})
+
+	got, _, err := kubeSvc.GetKubeConfigWithOptions(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", &KubernetesKubeConfigOptions{ExpirySeconds: PtrTo(int64(600))})
+	require.NoError(t, err)
+	require.Equal(t, blob, got.KubeconfigYAML)
+	assert.Equal(t, "expiry_seconds=600", gotQuery)
+
+	got, _, err = kubeSvc.GetKubeConfigWithOptions(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", &KubernetesKubeConfigOptions{})
+	require.NoError(t, err)
+	require.Equal(t, blob, got.KubeconfigYAML)
+	assert.Equal(t, "", gotQuery)
+
+	_, _, err = kubeSvc.GetKubeConfigWithOptions(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", gotQuery)
+}
+
 func TestKubernetesClusters_GetCredentials(t *testing.T) {
 	setup()
 	defer teardown()
@@ -659,6 +931,86 @@ func TestKubernetesClusters_Create(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusters_CreateWithOptions_IdempotencyKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		assert.Equal(t, "4f6c71e2-1e90-4762-9fee-6cc4a0a9f2cf", r.Header.Get("Idempotency-Key"))
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "antoine-test-cluster"}}`)
+	})
+
+	create := &KubernetesClusterCreateRequest{
+		Name:        "antoine-test-cluster",
+		RegionSlug:  "s2r1",
+		VersionSlug: "1.10.0-gen0",
+		NodePools:   []*KubernetesNodePoolCreateRequest{{Name: "pool-a", Size: "s-1vcpu-1gb", Count: 1}},
+	}
+	got, _, err := kubeSvc.CreateWithOptions(ctx, create, &KubernetesClusterCreateOptions{IdempotencyKey: "4f6c71e2-1e90-4762-9fee-6cc4a0a9f2cf"})
+	require.NoError(t, err)
+	assert.Equal(t, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", got.ID)
+}
+
+func TestKubernetesClusters_Create_NoIdempotencyKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		_, ok := r.Header["Idempotency-Key"]
+		assert.False(t, ok)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+
+	_, _, err := kubeSvc.Create(ctx, &KubernetesClusterCreateRequest{
+		Name:        "antoine-test-cluster",
+		RegionSlug:  "s2r1",
+		VersionSlug: "1.10.0-gen0",
+		NodePools:   []*KubernetesNodePoolCreateRequest{{Name: "pool-a", Size: "s-1vcpu-1gb", Count: 1}},
+	})
+	require.NoError(t, err)
+}
+
+func TestKubernetesRequests_StableJSON(t *testing.T) {
+	labels := func() map[string]string {
+		return map[string]string{"zone": "a", "app": "web", "tier": "frontend", "env": "prod", "owner": "payments"}
+	}
+	requests := map[string]func() interface{}{
+		"cluster create": func() interface{} {
+			return &KubernetesClusterCreateRequest{
+				Name: "prod",
+				NodePools: []*KubernetesNodePoolCreateRequest{
+					{Name: "pool-a", Labels: labels()},
+					{Name: "pool-b", Labels: labels()},
+				},
+			}
+		},
+		"node pool create": func() interface{} {
+			return &KubernetesNodePoolCreateRequest{Name: "pool-a", Labels: labels()}
+		},
+		"node pool update": func() interface{} {
+			return &KubernetesNodePoolUpdateRequest{Labels: labels()}
+		},
+	}
+	for name, newReq := range requests {
+		t.Run(name, func(t *testing.T) {
+			first, err := json.Marshal(newReq())
+			require.NoError(t, err)
+			for i := 0; i < 10; i++ {
+				again, err := json.Marshal(newReq())
+				require.NoError(t, err)
+				require.Equal(t, string(first), string(again))
+			}
+			assert.Contains(t, string(first), `"labels":{"app":"web","env":"prod","owner":"payments","tier":"frontend","zone":"a"}`)
+		})
+	}
+}
+
 func TestKubernetesClusters_Create_AutoScalePool(t *testing.T) {
 	setup()
 	defer teardown()
@@ -763,6 +1115,199 @@ func TestKubernetesClusters_Create_AutoScalePool(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesCluster_String(t *testing.T) {
+	cluster := KubernetesCluster{
+		ID:          "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		Name:        "prod",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.31.1-do.0",
+		HA:          true,
+		NodePools:   []*KubernetesNodePool{{Name: "workers"}, {Name: "batch"}},
+		Status:      &KubernetesClusterStatus{State: KubernetesClusterStatusRunning},
+	}
+	assert.Equal(t, "id=8d91899c-0739-4a1a-acc5-deadbeefbb8f name=prod region=nyc1 version=1.31.1-do.0 ha=true node_pools=2 state=running", cluster.String())
+	assert.Equal(t, "id= name= region= version= ha=false node_pools=0 state=", KubernetesCluster{}.String())
+	assert.Equal(t, cluster.String(), fmt.Sprintf("%v", &cluster))
+}
+
+func TestKubernetesNodePool_String(t *testing.T) {
+	pool := KubernetesNodePool{Name: "workers", Size: "s-2vcpu-4gb", Count: 3}
+	assert.Equal(t, "name=workers size=s-2vcpu-4gb count=3 autoscale=off", pool.String())
+
+	pool.AutoScale, pool.MinNodes, pool.MaxNodes = true, 1, 5
+	assert.Equal(t, "name=workers size=s-2vcpu-4gb count=3 autoscale=1-5", pool.String())
+}
+
+func TestKubernetesCluster_ClusterDNSDomain(t *testing.T) {
+	var custom KubernetesCluster
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "dns_domain": "k8s.internal"}`), &custom))
+	assert.Equal(t, "k8s.internal", custom.DNSDomain)
+	assert.Equal(t, "k8s.internal", custom.ClusterDNSDomain())
+
+	var unset KubernetesCluster
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}`), &unset))
+	assert.Empty(t, unset.DNSDomain)
+	assert.Equal(t, DefaultKubernetesClusterDNSDomain, unset.ClusterDNSDomain())
+}
+
+func TestKubernetesCluster_UpgradeDowntimeRisk(t *testing.T) {
+	pools := func(counts ...int) []*KubernetesNodePool {
+		var p []*KubernetesNodePool
+		for _, c := range counts {
+			p = append(p, &KubernetesNodePool{Count: c})
+		}
+		return p
+	}
+	tests := []struct {
+		name    string
+		cluster *KubernetesCluster
+		want    string
+	}{
+		{name: "HA and surge", cluster: &KubernetesCluster{HA: true, SurgeUpgrade: true, NodePools: pools(3, 2)}, want: KubernetesUpgradeRiskNone},
+		{name: "no HA", cluster: &KubernetesCluster{SurgeUpgrade: true, NodePools: pools(3, 2)}, want: KubernetesUpgradeRiskLow},
+		{name: "no surge", cluster: &KubernetesCluster{HA: true, NodePools: pools(3)}, want: KubernetesUpgradeRiskLow},
+		{name: "neither", cluster: &KubernetesCluster{NodePools: pools(2)}, want: KubernetesUpgradeRiskLow},
+		{name: "single node pool", cluster: &KubernetesCluster{HA: true, SurgeUpgrade: true, NodePools: pools(3, 1)}, want: KubernetesUpgradeRiskHigh},
+		{name: "no node pools", cluster: &KubernetesCluster{HA: true, SurgeUpgrade: true}, want: KubernetesUpgradeRiskHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cluster.UpgradeDowntimeRisk())
+		})
+	}
+}
+
+func TestKubernetesCluster_ToCreateRequest(t *testing.T) {
+	cluster := &KubernetesCluster{
+		ID:            "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		Name:          "prod",
+		RegionSlug:    "nyc1",
+		VersionSlug:   "1.31.1-do.0",
+		ClusterSubnet: "10.244.0.0/16",
+		ServiceSubnet: "10.245.0.0/16",
+		VPCUUID:       "880b7f98-f062-404d-b33c-458d545696f6",
+		Tags:          []string{"k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod"},
+		HA:            true,
+		AutoUpgrade:   true,
+		NodePools: []*KubernetesNodePool{
+			{
+				ID:     "pool-1",
+				Name:   "workers",
+				Size:   "s-2vcpu-4gb",
+				Count:  3,
+				Tags:   []string{"k8s", "k8s:worker", "workers"},
+				Labels: map[string]string{"priority": "high"},
+				Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}},
+				Nodes:  []*KubernetesNode{{ID: "node-1"}},
+			},
+			{
+				ID:        "pool-2",
+				Name:      "batch",
+				Size:      "c-4",
+				Count:     1,
+				AutoScale: true,
+				MinNodes:  1,
+				MaxNodes:  5,
+			},
+		},
+		MaintenancePolicy: &KubernetesMaintenancePolicy{StartTime: "00:00", Day: KubernetesMaintenanceDaySunday},
+		ClusterAutoscalerConfiguration: &KubernetesClusterAutoscalerConfiguration{
+			ScaleDownUnneededTime: PtrTo("5m"),
+		},
+		Status: &KubernetesClusterStatus{State: KubernetesClusterStatusRunning},
+	}
+
+	got := cluster.ToCreateRequest()
+	got.RegionSlug = "ams3"
+
+	assert.Equal(t, &KubernetesClusterCreateRequest{
+		Name:        "prod",
+		RegionSlug:  "ams3",
+		VersionSlug: "1.31.1-do.0",
+		Tags:        []string{"env:prod"},
+		HA:          true,
+		AutoUpgrade: true,
+		NodePools: []*KubernetesNodePoolCreateRequest{
+			{
+				Name:   "workers",
+				Size:   "s-2vcpu-4gb",
+				Count:  3,
+				Tags:   []string{"workers"},
+				Labels: map[string]string{"priority": "high"},
+				Taints: []Taint{{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule}},
+			},
+			{
+				Name:      "batch",
+				Size:      "c-4",
+				Count:     1,
+				AutoScale: true,
+				MinNodes:  1,
+				MaxNodes:  5,
+			},
+		},
+		MaintenancePolicy: &KubernetesMaintenancePolicy{StartTime: "00:00", Day: KubernetesMaintenanceDaySunday},
+		ClusterAutoscalerConfiguration: &KubernetesClusterAutoscalerConfiguration{
+			ScaleDownUnneededTime: PtrTo("5m"),
+		},
+	}, got)
+	require.NoError(t, got.Validate())
+
+	got.NodePools[0].Labels["priority"] = "low"
+	assert.Equal(t, "high", cluster.NodePools[0].Labels["priority"])
+	assert.Equal(t, "nyc1", cluster.RegionSlug)
+}
+
+func TestKubernetesClusters_ToUpdateRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"kubernetes_cluster": {
+				"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+				"name": "prod",
+				"tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod"],
+				"ha": true,
+				"auto_upgrade": false,
+				"surge_upgrade": true,
+				"maintenance_policy": {"start_time": "00:00", "duration": "4h0m0s", "day": "monday"},
+				"control_plane_firewall": {"enabled": true, "allowed_addresses": ["1.2.3.4/32"]},
+				"cluster_autoscaler_configuration": {"scale_down_utilization_threshold": 0.5, "scale_down_unneeded_time": "1m"}
+			}}`)
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{
+				"name": "prod-renamed",
+				"tags": ["env:prod"],
+				"auto_upgrade": false,
+				"surge_upgrade": true,
+				"ha": true,
+				"maintenance_policy": {"start_time": "00:00", "duration": "4h0m0s", "day": "monday"},
+				"control_plane_firewall": {"enabled": true, "allowed_addresses": ["1.2.3.4/32"]},
+				"cluster_autoscaler_configuration": {"scale_down_utilization_threshold": 0.5, "scale_down_unneeded_time": "1m"}
+			}`, string(body))
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "prod-renamed"}}`)
+		}
+	})
+
+	cluster, _, err := kubeSvc.Get(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+
+	update := cluster.ToUpdateRequest()
+	update.Name = "prod-renamed"
+
+	got, _, err := kubeSvc.Update(ctx, cluster.ID, update)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-renamed", got.Name)
+
+	update.ControlPlaneFirewall.AllowedAddresses[0] = "0.0.0.0/0"
+	assert.Equal(t, []string{"1.2.3.4/32"}, cluster.ControlPlaneFirewall.AllowedAddresses)
+}
+
 func TestKubernetesClusters_Update(t *testing.T) {
 	setup()
 	defer teardown()
@@ -969,6 +1514,155 @@ func TestKubernetesClusters_Update_FalseAutoUpgrade(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusterUpdateRequest_MarshalHA(t *testing.T) {
+	tests := []struct {
+		name string
+		ha   *bool
+		want string
+	}{
+		{name: "unset", ha: nil, want: `{}`},
+		{name: "disable", ha: PtrTo(false), want: `{"ha":false}`},
+		{name: "enable", ha: PtrTo(true), want: `{"ha":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := json.Marshal(&KubernetesClusterUpdateRequest{HA: tt.ha})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestKubernetesClusters_EnableHA(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	ha := false
+	puts := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "antoine", "tags": ["k8s", "env:prod"], "auto_upgrade": true, "ha": %t}}`, ha)
+		case http.MethodPut:
+			puts++
+			buf := new(bytes.Buffer)
+			_, err := buf.ReadFrom(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"name": "antoine", "tags": ["env:prod"], "auto_upgrade": true, "ha": true}`, buf.String())
+			ha = true
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "ha": true}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.EnableHA(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.True(t, got.HA)
+	assert.Equal(t, 1, puts)
+
+	// Enabling HA again changes nothing.
+	got, _, err = kubeSvc.EnableHA(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.True(t, got.HA)
+	assert.Equal(t, 1, puts)
+}
+
+func TestKubernetesClusters_DisableHA(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request to disable HA")
+	})
+
+	got, resp, err := kubeSvc.DisableHA(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.ErrorIs(t, err, ErrKubernetesHADowngradeUnsupported)
+	assert.Nil(t, got)
+	assert.Nil(t, resp)
+}
+
+func TestKubernetesClusters_PauseAutoUpgrade(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"kubernetes_cluster": {
+				"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+				"name": "prod",
+				"tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "env:prod"],
+				"maintenance_policy": {"start_time": "04:00", "duration": "4h0m0s", "day": "sunday"},
+				"auto_upgrade": true,
+				"surge_upgrade": true,
+				"ha": true,
+				"control_plane_firewall": {"enabled": true, "allowed_addresses": ["10.0.0.0/8"]}
+			}}`)
+		case http.MethodPut:
+			buf := new(bytes.Buffer)
+			_, err := buf.ReadFrom(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{
+				"name": "prod",
+				"tags": ["env:prod"],
+				"maintenance_policy": {"start_time": "04:00", "duration": "4h0m0s", "day": "sunday"},
+				"auto_upgrade": false,
+				"surge_upgrade": true,
+				"ha": true,
+				"control_plane_firewall": {"enabled": true, "allowed_addresses": ["10.0.0.0/8"]}
+			}`, buf.String())
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "auto_upgrade": false}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.PauseAutoUpgrade(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.False(t, got.AutoUpgrade)
+}
+
+func TestKubernetesClusters_ResumeAutoUpgrade(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	autoUpgrade, puts := false, 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "prod", "auto_upgrade": %t}}`, autoUpgrade)
+		case http.MethodPut:
+			puts++
+			buf := new(bytes.Buffer)
+			_, err := buf.ReadFrom(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"name": "prod", "auto_upgrade": true, "ha": false}`, buf.String())
+			autoUpgrade = true
+			fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "prod", "auto_upgrade": true}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.ResumeAutoUpgrade(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.True(t, got.AutoUpgrade)
+
+	// Resuming again changes nothing.
+	_, _, err = kubeSvc.ResumeAutoUpgrade(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+}
+
 func TestKubernetesClusters_Upgrade(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1116,8 +1810,214 @@ func TestKubernetesClusters_ListAssociatedResourcesForDeletion(t *testing.T) {
 
 }
 
-func TestKubernetesClusters_CreateNodePool(t *testing.T) {
-	setup()
+func TestKubernetesAssociatedResources_Select(t *testing.T) {
+	var resources KubernetesAssociatedResources
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"volumes": [
+			{"id": "4d8d5ee3-002e-11ed-b5b6-0a58ac144a01", "name": "pvc-data-postgres-0"},
+			{"id": "4d8d5ee3-002e-11ed-b5b6-0a58ac144a02", "name": "pvc-data-redis-0"}
+		],
+		"volume_snapshots": [
+			{"id": "edb0478d-7436-11ea-86e6-0a58ac144b91", "name": "snapshot-postgres-2024-06-01"},
+			{"id": "edb0478d-7436-11ea-86e6-0a58ac144b92", "name": "snapshot-redis-2024-06-01"},
+			{"id": "edb0478d-7436-11ea-86e6-0a58ac144b93", "name": "snapshot-postgres-2024-06-02"}
+		],
+		"load_balancers": [
+			{"id": "4de7ac8b-495b-4884-9a69-1050c6793cd6", "name": "ingress"}
+		]
+	}`), &resources))
+
+	t.Run("all", func(t *testing.T) {
+		assert.Equal(t, &KubernetesClusterDeleteSelectiveRequest{
+			Volumes:         []string{"4d8d5ee3-002e-11ed-b5b6-0a58ac144a01", "4d8d5ee3-002e-11ed-b5b6-0a58ac144a02"},
+			VolumeSnapshots: []string{"edb0478d-7436-11ea-86e6-0a58ac144b91", "edb0478d-7436-11ea-86e6-0a58ac144b92", "edb0478d-7436-11ea-86e6-0a58ac144b93"},
+			LoadBalancers:   []string{"4de7ac8b-495b-4884-9a69-1050c6793cd6"},
+		}, resources.SelectAll())
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		got := resources.SelectByName(regexp.MustCompile(`^snapshot-postgres-`))
+		assert.Equal(t, &KubernetesClusterDeleteSelectiveRequest{
+			Volumes:         []string{},
+			VolumeSnapshots: []string{"edb0478d-7436-11ea-86e6-0a58ac144b91", "edb0478d-7436-11ea-86e6-0a58ac144b93"},
+			LoadBalancers:   []string{},
+		}, got)
+
+		body, err := json.Marshal(got)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"volumes": [], "volume_snapshots": ["edb0478d-7436-11ea-86e6-0a58ac144b91", "edb0478d-7436-11ea-86e6-0a58ac144b93"], "load_balancers": []}`, string(body))
+	})
+
+	t.Run("nil regexp", func(t *testing.T) {
+		assert.Equal(t, &KubernetesClusterDeleteSelectiveRequest{
+			Volumes:         []string{},
+			VolumeSnapshots: []string{},
+			LoadBalancers:   []string{},
+		}, resources.SelectByName(nil))
+	})
+}
+
+func TestKubernetesClusters_PreflightDangerousDelete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {
+			"id": "deadbeef-dead-4aa5-beef-deadbeef347d",
+			"name": "prod-cluster",
+			"node_pools": [{"id": "pool-1", "name": "workers", "nodes": [{"id": "node-1"}, {"id": "node-2"}]}]
+		}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/destroy_with_associated_resources", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{
+			"volumes": [{"id": "2241", "name": "test-volume-1"}],
+			"volume_snapshots": [],
+			"load_balancers": [{"id": "4235", "name": "test-load-balancer-1"}]
+		}`)
+	})
+
+	got, _, err := kubeSvc.PreflightDangerousDelete(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.NoError(t, err)
+	assert.Equal(t, "deadbeef-dead-4aa5-beef-deadbeef347d", got.ClusterID)
+	assert.Equal(t, "prod-cluster", got.ClusterName)
+	assert.Equal(t, 2, got.NodeCount())
+	assert.Equal(t, []*AssociatedResource{{ID: "2241", Name: "test-volume-1"}}, got.Volumes)
+	assert.Empty(t, got.VolumeSnapshots)
+	assert.Equal(t, []*AssociatedResource{{ID: "4235", Name: "test-load-balancer-1"}}, got.LoadBalancers)
+	assert.True(t, got.ConfirmsName("prod-cluster"))
+	assert.False(t, got.ConfirmsName("prod"))
+}
+
+func TestKubernetesClusters_PreflightDangerousDelete_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := kubeSvc.PreflightDangerousDelete(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	assert.ErrorIs(t, err, ErrKubernetesClusterNotFound)
+}
+
+func TestKubernetesClusters_GetClusterStatusMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	since := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	want := []*KubernetesClusterStatusMessage{
+		{
+			Message:   "Resource provisioning in progress",
+			Timestamp: time.Date(2024, 6, 1, 8, 10, 0, 0, time.UTC),
+		},
+	}
+	jBlob := `
+{
+	"messages": [
+		{
+			"message": "Resource provisioning in progress",
+			"timestamp": "2024-06-01T08:10:00Z"
+		}
+	]
+}`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/status_messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		require.Equal(t, "2024-06-01T08:00:00Z", r.URL.Query().Get("since"))
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.GetClusterStatusMessages(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesGetClusterStatusMessagesRequest{Since: &since})
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestKubernetesClusters_GetRecentClusterStatusMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/status_messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"messages": [
+			{"message": "Cluster upgrade to 1.30.2-do.0 started", "timestamp": "2024-06-03T09:00:00Z"},
+			{"message": "Resource provisioning in progress", "timestamp": "2024-06-01T08:10:00Z"},
+			{"message": "Cluster is running", "timestamp": "2024-06-01T08:15:00Z"}
+		]}`)
+	})
+
+	got, _, err := kubeSvc.GetRecentClusterStatusMessages(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", 2)
+	require.NoError(t, err)
+	require.Equal(t, []*KubernetesClusterStatusMessage{
+		{Message: "Cluster is running", Timestamp: time.Date(2024, 6, 1, 8, 15, 0, 0, time.UTC)},
+		{Message: "Cluster upgrade to 1.30.2-do.0 started", Timestamp: time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)},
+	}, got)
+
+	got, _, err = kubeSvc.GetRecentClusterStatusMessages(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", 10)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "Resource provisioning in progress", got[0].Message)
+
+	_, _, err = kubeSvc.GetRecentClusterStatusMessages(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", 0)
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_GetClusterHistory(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	jBlob := `
+{
+	"messages": [
+		{
+			"message": "Cluster upgrade to 1.30.2-do.0 started",
+			"timestamp": "2024-06-03T09:00:00Z"
+		},
+		{
+			"message": "Resource provisioning in progress",
+			"timestamp": "2024-06-01T08:10:00Z"
+		},
+		{
+			"message": "Cluster is running",
+			"timestamp": "2024-06-01T08:15:00Z"
+		}
+	]
+}`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/status_messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		require.Empty(t, r.URL.Query())
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.GetClusterHistory(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", nil)
+	require.NoError(t, err)
+	require.Equal(t, []*KubernetesClusterEvent{
+		{Timestamp: time.Date(2024, 6, 1, 8, 10, 0, 0, time.UTC), Message: "Resource provisioning in progress"},
+		{Timestamp: time.Date(2024, 6, 1, 8, 15, 0, 0, time.UTC), Message: "Cluster is running"},
+		{Timestamp: time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC), Message: "Cluster upgrade to 1.30.2-do.0 started"},
+	}, got)
+
+	got, _, err = kubeSvc.GetClusterHistory(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &ListOptions{Page: 2, PerPage: 2})
+	require.NoError(t, err)
+	require.Equal(t, []*KubernetesClusterEvent{
+		{Timestamp: time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC), Message: "Cluster upgrade to 1.30.2-do.0 started"},
+	}, got)
+}
+
+func TestKubernetesClusters_CreateNodePool(t *testing.T) {
+	setup()
 	defer teardown()
 
 	kubeSvc := client.Kubernetes
@@ -1269,6 +2169,85 @@ func TestKubernetesClusters_GetNodePool(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusters_GetNode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-0739-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8a", "nodes": [
+			{"id": "node-1", "name": "pool-a-1", "status": {"state": "running"}},
+			{"id": "node-2", "name": "pool-a-2", "status": {"state": "provisioning"}}
+		]}}`)
+	})
+
+	got, _, err := kubeSvc.GetNode(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a", "node-2")
+	require.NoError(t, err)
+	assert.Equal(t, &KubernetesNode{ID: "node-2", Name: "pool-a-2", Status: &KubernetesNodeStatus{State: "provisioning"}}, got)
+
+	_, _, err = kubeSvc.GetNode(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a", "node-3")
+	require.ErrorIs(t, err, ErrKubernetesNodeNotFound)
+	assert.Contains(t, err.Error(), "node-3")
+}
+
+func TestKubernetesClusters_GetNodePoolStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	jBlob := `
+{
+	"node_pool": {
+		"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8a",
+		"size": "s-1vcpu-1gb",
+		"count": 3,
+		"name": "pool-a",
+		"nodes": [
+			{"id": "node-1", "name": "pool-a-1", "status": {"state": "running"}},
+			{"id": "node-2", "name": "pool-a-2", "status": {"state": "error", "message": "droplet limit exceeded"}},
+			{"id": "node-3", "name": "pool-a-3", "status": {"state": "provisioning"}}
+		]
+	}
+}`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-0739-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.GetNodePoolStatus(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-0739-4a1a-acc5-deadbeefbb8a")
+	require.NoError(t, err)
+	assert.Equal(t, "error", got.State)
+	assert.Equal(t, map[string]int{"running": 1, "error": 1, "provisioning": 1}, got.NodeStates)
+	require.Len(t, got.FailedNodes, 1)
+	assert.Equal(t, "node-2", got.FailedNodes[0].ID)
+	assert.Equal(t, []string{"droplet limit exceeded"}, got.Errors)
+}
+
+func TestKubernetesNodePoolStatus_State(t *testing.T) {
+	node := func(state string) *KubernetesNode {
+		return &KubernetesNode{Status: &KubernetesNodeStatus{State: state}}
+	}
+	tests := []struct {
+		name  string
+		nodes []*KubernetesNode
+		want  string
+	}{
+		{name: "all running", nodes: []*KubernetesNode{node("running"), node("running")}, want: "running"},
+		{name: "provisioning", nodes: []*KubernetesNode{node("running"), node("provisioning")}, want: "provisioning"},
+		{name: "error wins", nodes: []*KubernetesNode{node("error"), node("provisioning")}, want: "error"},
+		{name: "no status", nodes: []*KubernetesNode{{}}, want: "provisioning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nodePoolStatus(&KubernetesNodePool{Nodes: tt.nodes}).State)
+		})
+	}
+}
+
 func TestKubernetesClusters_ListNodePools(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1343,6 +2322,229 @@ func TestKubernetesClusters_ListNodePools(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusters_ListNodePoolsAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		assert.Equal(t, "200", r.URL.Query().Get("per_page"))
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			fmt.Fprint(w, `{
+				"node_pools": [{"id": "pool-1"}],
+				"links": {"pages": {"next": "https://api.digitalocean.com/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools?page=2", "last": "https://api.digitalocean.com/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools?page=2"}}
+			}`)
+		case "2":
+			fmt.Fprint(w, `{"node_pools": [{"id": "pool-2"}]}`)
+		default:
+			t.Fatalf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	got, _, err := kubeSvc.ListNodePoolsAll(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "pool-1", got[0].ID)
+	assert.Equal(t, "pool-2", got[1].ID)
+}
+
+func TestKubernetesClusters_ListAllNodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [
+			{"id": "pool-id-b", "name": "pool-b", "nodes": [
+				{"id": "b-2", "name": "pool-b-2"},
+				{"id": "b-1", "name": "pool-b-1"}
+			]},
+			{"id": "pool-id-a", "name": "pool-a", "nodes": [
+				{"id": "a-3", "name": "pool-a-3"},
+				{"id": "a-1", "name": "pool-a-1"},
+				{"id": "a-2", "name": "pool-a-2"}
+			]}
+		]}`)
+	})
+
+	got, err := kubeSvc.ListAllNodes(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+
+	type nodeRef struct{ pool, poolName, node string }
+	var refs []nodeRef
+	for _, n := range got {
+		refs = append(refs, nodeRef{n.PoolID, n.PoolName, n.ID})
+	}
+	assert.Equal(t, []nodeRef{
+		{"pool-id-a", "pool-a", "a-1"},
+		{"pool-id-a", "pool-a", "a-2"},
+		{"pool-id-a", "pool-a", "a-3"},
+		{"pool-id-b", "pool-b", "b-1"},
+		{"pool-id-b", "pool-b", "b-2"},
+	}, refs)
+}
+
+func TestKubernetesClusters_ListNodePoolsForClusters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [{"id": "pool-a", "name": "pool-a", "count": 2}]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [{"id": "pool-b", "name": "pool-b", "count": 1}, {"id": "pool-c", "name": "pool-c", "count": 3}]}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/00000000-dead-4aa5-beef-deadbeef0000/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "cluster not found"}`)
+	})
+
+	got, err := kubeSvc.ListNodePoolsForClusters(ctx, []string{
+		"8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		"deadbeef-dead-4aa5-beef-deadbeef347d",
+	}, 2)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]*KubernetesNodePool{
+		"8d91899c-0739-4a1a-acc5-deadbeefbb8f": {
+			{ID: "pool-a", Name: "pool-a", Count: 2},
+		},
+		"deadbeef-dead-4aa5-beef-deadbeef347d": {
+			{ID: "pool-b", Name: "pool-b", Count: 1},
+			{ID: "pool-c", Name: "pool-c", Count: 3},
+		},
+	}, got)
+
+	got, err = kubeSvc.ListNodePoolsForClusters(ctx, []string{
+		"8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		"00000000-dead-4aa5-beef-deadbeef0000",
+	}, 0)
+	require.ErrorContains(t, err, "00000000-dead-4aa5-beef-deadbeef0000")
+	require.Len(t, got, 1)
+	require.Contains(t, got, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+}
+
+func TestKubernetesClusters_ListUnderMinNodePools(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [
+			{
+				"id": "pool-healthy", "auto_scale": true, "min_nodes": 2, "max_nodes": 5,
+				"nodes": [{"status": {"state": "running"}}, {"status": {"state": "running"}}]
+			},
+			{
+				"id": "pool-under-min", "auto_scale": true, "min_nodes": 3, "max_nodes": 5,
+				"nodes": [{"status": {"state": "running"}}, {"status": {"state": "error"}}, {"status": {"state": "provisioning"}}]
+			},
+			{
+				"id": "pool-fixed", "count": 2,
+				"nodes": [{"status": {"state": "error"}}, {"status": {"state": "error"}}]
+			}
+		]}`)
+	})
+
+	got, _, err := kubeSvc.ListUnderMinNodePools(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "pool-under-min", got[0].ID)
+}
+
+func TestKubernetesClusters_CurrentSurgeNodes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pools": [
+			{"id": "pool-a", "count": 2, "nodes": [{"id": "a-1"}, {"id": "a-2"}, {"id": "a-3"}, {"id": "a-4"}]},
+			{"id": "pool-b", "count": 1, "nodes": [{"id": "b-1"}, {"id": "b-2"}]},
+			{"id": "pool-c", "count": 3, "nodes": [{"id": "c-1"}]}
+		]}`)
+	})
+
+	got, _, err := kubeSvc.CurrentSurgeNodes(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+}
+
+func TestKubernetesClusters_UpdateNodePoolWithRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	count, updates := 3, 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "name": "pool-a", "count": %d}}`, count)
+		case http.MethodPut:
+			updates++
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			if updates == 1 {
+				// Someone else scaled the pool in the meantime.
+				count = 4
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, `{"id": "conflict", "message": "node pool was modified"}`)
+				return
+			}
+			count = *v.Count
+			fmt.Fprintf(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "name": "pool-a", "count": %d}}`, count)
+		}
+	})
+
+	var seen []int
+	got, _, err := kubeSvc.UpdateNodePoolWithRetry(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		seen = append(seen, pool.Count)
+		return &KubernetesNodePoolUpdateRequest{Count: PtrTo(pool.Count + 1)}
+	}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, got.Count)
+	assert.Equal(t, []int{3, 4}, seen)
+	assert.Equal(t, 2, updates)
+}
+
+func TestKubernetesClusters_UpdateNodePoolWithRetry_Exhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	updates := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "count": 3}}`)
+		case http.MethodPut:
+			updates++
+			w.WriteHeader(http.StatusConflict)
+		}
+	})
+
+	_, resp, err := kubeSvc.UpdateNodePoolWithRetry(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(pool *KubernetesNodePool) *KubernetesNodePoolUpdateRequest {
+		return &KubernetesNodePoolUpdateRequest{Count: PtrTo(pool.Count + 1)}
+	}, 1)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, 2, updates)
+}
+
 func TestKubernetesClusters_UpdateNodePool(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1394,12 +2596,408 @@ func TestKubernetesClusters_UpdateNodePool(t *testing.T) {
 		fmt.Fprint(w, jBlob)
 	})
 
-	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestKubernetesClusters_UpdateNodePool_ZeroCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	want := &KubernetesNodePool{
+		ID:        "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		Name:      "name",
+		Size:      "s-1vcpu-1gb",
+		Count:     0,
+		Tags:      []string{"tag-1", "tag-2"},
+		AutoScale: false,
+		MinNodes:  0,
+		MaxNodes:  0,
+	}
+	updateRequest := &KubernetesNodePoolUpdateRequest{
+		Count: PtrTo(0),
+	}
+
+	jBlob := `
+{
+	"node_pool": {
+		"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		"size": "s-1vcpu-1gb",
+		"count": 0,
+		"name": "name",
+		"tags": [
+			"tag-1", "tag-2"
+		]
+	}
+}`
+
+	expectedReqJSON := `{"count":0}
+`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		require.Equal(t, expectedReqJSON, buf.String())
+
+		v := new(KubernetesNodePoolUpdateRequest)
+		err := json.NewDecoder(buf).Decode(v)
+		require.NoError(t, err)
+
+		testMethod(t, r, http.MethodPut)
+		require.Equal(t, v, updateRequest)
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestKubernetesClusters_UpdateNodePool_AutoScale(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	want := &KubernetesNodePool{
+		ID:        "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		Name:      "name",
+		Size:      "s-1vcpu-1gb",
+		Count:     4,
+		Tags:      []string{"tag-1", "tag-2"},
+		AutoScale: true,
+		MinNodes:  0,
+		MaxNodes:  10,
+	}
+	updateRequest := &KubernetesNodePoolUpdateRequest{
+		AutoScale: PtrTo(true),
+		MinNodes:  PtrTo(0),
+		MaxNodes:  PtrTo(10),
+	}
+
+	jBlob := `
+{
+	"node_pool": {
+		"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		"size": "s-1vcpu-1gb",
+		"count": 4,
+		"name": "name",
+		"tags": [
+			"tag-1", "tag-2"
+		],
+		"auto_scale": true,
+		"min_nodes": 0,
+		"max_nodes": 10
+	}
+}`
+
+	expectedReqJSON := `{"auto_scale":true,"min_nodes":0,"max_nodes":10}
+`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		require.Equal(t, expectedReqJSON, buf.String())
+
+		v := new(KubernetesNodePoolUpdateRequest)
+		err := json.NewDecoder(buf).Decode(v)
+		require.NoError(t, err)
+
+		testMethod(t, r, http.MethodPut)
+		require.Equal(t, v, updateRequest)
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestKubernetesClusters_ScaleNodePool(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	want := &KubernetesNodePool{
+		ID:    "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		Name:  "name",
+		Size:  "s-1vcpu-1gb",
+		Count: 7,
+	}
+
+	jBlob := `
+{
+	"node_pool": {
+		"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		"size": "s-1vcpu-1gb",
+		"count": 7,
+		"name": "name"
+	}
+}`
+
+	expectedReqJSON := `{"count":7}
+`
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		require.Equal(t, expectedReqJSON, buf.String())
+
+		testMethod(t, r, http.MethodPut)
+		fmt.Fprint(w, jBlob)
+	})
+
+	got, _, err := kubeSvc.ScaleNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", 7)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestKubernetesClusters_ScaleNodePool_NegativeCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for a negative count")
+	})
+
+	_, _, err := kubeSvc.ScaleNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", -1)
+	require.Equal(t, NewArgError("count", "cannot be less than 0"), err)
+}
+
+func TestKubernetesClusters_AddTaint(t *testing.T) {
+	tests := []struct {
+		name       string
+		taint      Taint
+		wantTaints []Taint
+	}{
+		{
+			name:  "new key",
+			taint: Taint{Key: "gpu", Value: "true", Effect: TaintEffectNoSchedule},
+			wantTaints: []Taint{
+				{Key: "dedicated", Value: "db", Effect: TaintEffectNoSchedule},
+				{Key: "gpu", Value: "true", Effect: TaintEffectNoSchedule},
+			},
+		},
+		{
+			name:  "existing key is overwritten",
+			taint: Taint{Key: "dedicated", Value: "cache", Effect: TaintEffectNoExecute},
+			wantTaints: []Taint{
+				{Key: "dedicated", Value: "cache", Effect: TaintEffectNoExecute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			kubeSvc := client.Kubernetes
+
+			mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "name": "pool-a", "taints": [{"key": "dedicated", "value": "db", "effect": "NoSchedule"}]}}`)
+				case http.MethodPut:
+					v := new(KubernetesNodePoolUpdateRequest)
+					require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+					require.NotNil(t, v.Taints)
+					assert.Equal(t, tt.wantTaints, *v.Taints)
+					require.NoError(t, json.NewEncoder(w).Encode(map[string]*KubernetesNodePool{
+						"node_pool": {ID: "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", Name: "pool-a", Taints: *v.Taints},
+					}))
+				default:
+					t.Fatalf("unexpected method %s", r.Method)
+				}
+			})
+
+			got, _, err := kubeSvc.AddTaint(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", tt.taint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTaints, got.Taints)
+		})
+	}
+}
+
+func TestKubernetesClusters_RemoveTaint(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "taints": [{"key": "dedicated", "value": "db", "effect": "NoSchedule"}, {"key": "gpu", "effect": "NoExecute"}]}}`)
+		case http.MethodPut:
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			require.NotNil(t, v.Taints)
+			assert.Equal(t, []Taint{{Key: "gpu", Effect: TaintEffectNoExecute}}, *v.Taints)
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "taints": [{"key": "gpu", "effect": "NoExecute"}]}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.RemoveTaint(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "dedicated")
+	require.NoError(t, err)
+	assert.Equal(t, []Taint{{Key: "gpu", Effect: TaintEffectNoExecute}}, got.Taints)
+}
+
+func TestKubernetesClusters_RemoveTaint_Missing(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "taints": [{"key": "gpu", "effect": "NoExecute"}]}}`)
+	})
+
+	got, _, err := kubeSvc.RemoveTaint(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "dedicated")
+	require.NoError(t, err)
+	assert.Equal(t, []Taint{{Key: "gpu", Effect: TaintEffectNoExecute}}, got.Taints)
+}
+
+func TestKubernetesClusters_MergeLabels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "labels": {"team": "payments", "tier": "backend"}}}`)
+		case http.MethodPut:
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, map[string]string{"team": "payments", "tier": "frontend", "env": "prod"}, v.Labels)
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "labels": {"team": "payments", "tier": "frontend", "env": "prod"}}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.MergeLabels(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", map[string]string{"tier": "frontend", "env": "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "payments", got.Labels["team"])
+}
+
+func TestKubernetesClusters_RemoveLabels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	puts := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "labels": {"team": "payments", "tier": "backend"}}}`)
+		case http.MethodPut:
+			puts++
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, map[string]string{"team": "payments"}, v.Labels)
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "labels": {"team": "payments"}}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	got, _, err := kubeSvc.RemoveLabels(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "tier", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments"}, got.Labels)
+	assert.Equal(t, 1, puts)
+
+	// Removing labels the node pool does not have changes nothing.
+	_, _, err = kubeSvc.RemoveLabels(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+
+	// The last labels cannot be removed, as an empty set is not sent.
+	_, _, err = kubeSvc.RemoveLabels(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "team", "tier")
+	assert.ErrorIs(t, err, ErrKubernetesClearUnsupported)
+	assert.Equal(t, 1, puts)
+}
+
+func TestKubernetesClusters_AddTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	puts := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "team:payments", "env:prod"]}}`)
+		case http.MethodPut:
+			puts++
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, []string{"team:payments", "env:prod", "tier:backend"}, v.Tags)
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "tags": ["k8s", "k8s:8d91899c-0739-4a1a-acc5-deadbeefbb8f", "team:payments", "env:prod", "tier:backend"]}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	_, _, err := kubeSvc.AddTags(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "env:prod", "tier:backend", "tier:backend")
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+
+	// Adding tags the node pool already has changes nothing.
+	_, _, err = kubeSvc.AddTags(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "env:prod")
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+}
+
+func TestKubernetesClusters_RemoveTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	puts := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "tags": ["k8s", "team:payments", "env:prod", "tier:backend"]}}`)
+		case http.MethodPut:
+			puts++
+			v := new(KubernetesNodePoolUpdateRequest)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(v))
+			assert.Equal(t, []string{"team:payments", "tier:backend"}, v.Tags)
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "tags": ["k8s", "team:payments", "tier:backend"]}}`)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	_, _, err := kubeSvc.RemoveTags(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "env:prod", "missing")
 	require.NoError(t, err)
-	require.Equal(t, want, got)
+	assert.Equal(t, 1, puts)
+
+	// Removing tags the node pool does not have changes nothing.
+	_, _, err = kubeSvc.RemoveTags(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, 1, puts)
+
+	// The last user tags cannot be removed, as an empty list is not sent.
+	_, _, err = kubeSvc.RemoveTags(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "team:payments", "env:prod", "tier:backend")
+	assert.ErrorIs(t, err, ErrKubernetesClearUnsupported)
+	assert.Equal(t, 1, puts)
 }
 
-func TestKubernetesClusters_UpdateNodePool_ZeroCount(t *testing.T) {
+func TestKubernetesClusters_EnableAutoScale(t *testing.T) {
 	setup()
 	defer teardown()
 
@@ -1409,14 +3007,10 @@ func TestKubernetesClusters_UpdateNodePool_ZeroCount(t *testing.T) {
 		ID:        "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
 		Name:      "name",
 		Size:      "s-1vcpu-1gb",
-		Count:     0,
-		Tags:      []string{"tag-1", "tag-2"},
-		AutoScale: false,
-		MinNodes:  0,
-		MaxNodes:  0,
-	}
-	updateRequest := &KubernetesNodePoolUpdateRequest{
-		Count: PtrTo(0),
+		Count:     2,
+		AutoScale: true,
+		MinNodes:  1,
+		MaxNodes:  5,
 	}
 
 	jBlob := `
@@ -1424,15 +3018,15 @@ func TestKubernetesClusters_UpdateNodePool_ZeroCount(t *testing.T) {
 	"node_pool": {
 		"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
 		"size": "s-1vcpu-1gb",
-		"count": 0,
+		"count": 2,
 		"name": "name",
-		"tags": [
-			"tag-1", "tag-2"
-		]
+		"auto_scale": true,
+		"min_nodes": 1,
+		"max_nodes": 5
 	}
 }`
 
-	expectedReqJSON := `{"count":0}
+	expectedReqJSON := `{"auto_scale":true,"min_nodes":1,"max_nodes":5}
 `
 
 	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
@@ -1440,40 +3034,63 @@ func TestKubernetesClusters_UpdateNodePool_ZeroCount(t *testing.T) {
 		buf.ReadFrom(r.Body)
 		require.Equal(t, expectedReqJSON, buf.String())
 
-		v := new(KubernetesNodePoolUpdateRequest)
-		err := json.NewDecoder(buf).Decode(v)
-		require.NoError(t, err)
-
 		testMethod(t, r, http.MethodPut)
-		require.Equal(t, v, updateRequest)
 		fmt.Fprint(w, jBlob)
 	})
 
-	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	got, _, err := kubeSvc.EnableAutoScale(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", 1, 5)
 	require.NoError(t, err)
 	require.Equal(t, want, got)
 }
 
-func TestKubernetesClusters_UpdateNodePool_AutoScale(t *testing.T) {
+func TestKubernetesClusters_EnableAutoScale_InvalidBounds(t *testing.T) {
 	setup()
 	defer teardown()
 
 	kubeSvc := client.Kubernetes
 
-	want := &KubernetesNodePool{
-		ID:        "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
-		Name:      "name",
-		Size:      "s-1vcpu-1gb",
-		Count:     4,
-		Tags:      []string{"tag-1", "tag-2"},
-		AutoScale: true,
-		MinNodes:  0,
-		MaxNodes:  10,
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for invalid autoscale bounds")
+	})
+
+	tests := []struct {
+		name     string
+		min, max int
+		wantErr  error
+	}{
+		{
+			name:    "negative min",
+			min:     -1,
+			max:     3,
+			wantErr: NewArgError("min", "cannot be less than 0"),
+		},
+		{
+			name:    "min above max",
+			min:     4,
+			max:     3,
+			wantErr: NewArgError("max", "cannot be less than min"),
+		},
 	}
-	updateRequest := &KubernetesNodePoolUpdateRequest{
-		AutoScale: PtrTo(true),
-		MinNodes:  PtrTo(0),
-		MaxNodes:  PtrTo(10),
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := kubeSvc.EnableAutoScale(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", tt.min, tt.max)
+			require.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestKubernetesClusters_DisableAutoScale(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	want := &KubernetesNodePool{
+		ID:    "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
+		Name:  "name",
+		Size:  "s-1vcpu-1gb",
+		Count: 2,
 	}
 
 	jBlob := `
@@ -1481,18 +3098,13 @@ func TestKubernetesClusters_UpdateNodePool_AutoScale(t *testing.T) {
 	"node_pool": {
 		"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a",
 		"size": "s-1vcpu-1gb",
-		"count": 4,
+		"count": 2,
 		"name": "name",
-		"tags": [
-			"tag-1", "tag-2"
-		],
-		"auto_scale": true,
-		"min_nodes": 0,
-		"max_nodes": 10
+		"auto_scale": false
 	}
 }`
 
-	expectedReqJSON := `{"auto_scale":true,"min_nodes":0,"max_nodes":10}
+	expectedReqJSON := `{"auto_scale":false}
 `
 
 	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
@@ -1500,16 +3112,11 @@ func TestKubernetesClusters_UpdateNodePool_AutoScale(t *testing.T) {
 		buf.ReadFrom(r.Body)
 		require.Equal(t, expectedReqJSON, buf.String())
 
-		v := new(KubernetesNodePoolUpdateRequest)
-		err := json.NewDecoder(buf).Decode(v)
-		require.NoError(t, err)
-
 		testMethod(t, r, http.MethodPut)
-		require.Equal(t, v, updateRequest)
 		fmt.Fprint(w, jBlob)
 	})
 
-	got, _, err := kubeSvc.UpdateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", updateRequest)
+	got, _, err := kubeSvc.DisableAutoScale(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a")
 	require.NoError(t, err)
 	require.Equal(t, want, got)
 }
@@ -1528,6 +3135,38 @@ func TestKubernetesClusters_DeleteNodePool(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestKubernetesClusters_DeleteNodePools(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var deleted []string
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/node_pools/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		poolID := strings.TrimPrefix(r.URL.Path, "/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d/node_pools/")
+		if poolID == "pool-2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"id": "server_error", "message": "internal server error"}`)
+			return
+		}
+		deleted = append(deleted, poolID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := kubeSvc.DeleteNodePools(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d", []string{"pool-1", "pool-2", "pool-3"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pool-2")
+	assert.NotContains(t, err.Error(), "pool-1")
+	assert.NotContains(t, err.Error(), "pool-3")
+	var errResp *ErrorResponse
+	require.ErrorAs(t, err, &errResp)
+	assert.Equal(t, http.StatusInternalServerError, errResp.Response.StatusCode)
+
+	assert.Equal(t, []string{"pool-1", "pool-3"}, deleted)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
 func TestKubernetesClusters_DeleteNode(t *testing.T) {
 	t.Run("default", func(t *testing.T) {
 		setup()
@@ -1667,6 +3306,84 @@ func TestKubernetesVersions_List(t *testing.T) {
 	require.Equal(t, want, got)
 }
 
+func TestKubernetesClusters_CachedOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes.(*KubernetesServiceOp)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kubeSvc.now = func() time.Time { return now }
+
+	calls := 0
+	mux.HandleFunc("/v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		calls++
+		fmt.Fprint(w, `{"options": {"versions": [{"slug": "1.31.1-do.0", "kubernetes_version": "1.31.1"}]}}`)
+	})
+
+	const ttl = time.Minute
+	first, err := kubeSvc.CachedOptions(ctx, ttl)
+	require.NoError(t, err)
+	now = now.Add(ttl - time.Second)
+	second, err := kubeSvc.CachedOptions(ctx, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Same(t, first, second)
+	assert.Equal(t, "1.31.1-do.0", second.Versions[0].Slug)
+
+	now = now.Add(time.Second)
+	_, err = kubeSvc.CachedOptions(ctx, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	_, err = kubeSvc.CachedOptions(ctx, 0)
+	require.Equal(t, NewArgError("ttl", "must be greater than 0"), err)
+}
+
+func TestKubernetesClusters_CachedOptions_Concurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	mux.HandleFunc("/v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		fmt.Fprint(w, `{"options": {"versions": [{"slug": "1.31.1-do.0", "kubernetes_version": "1.31.1"}]}}`)
+	})
+
+	type result struct {
+		options *KubernetesOptions
+		err     error
+	}
+	results := make(chan result, 2)
+	fetch := func() {
+		options, err := kubeSvc.CachedOptions(ctx, time.Minute)
+		results <- result{options, err}
+	}
+	go fetch()
+	<-started
+	go fetch()
+
+	// A waiter whose context is done does not wait for the fetch in flight.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := kubeSvc.CachedOptions(canceled, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	first, second := <-results, <-results
+	require.NoError(t, first.err)
+	require.NoError(t, second.err)
+	assert.Same(t, first.options, second.options)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
 func TestKubernetesClusterRegistry_Add(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1717,6 +3434,79 @@ func TestKubernetesClusterRegistry_Remove(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestKubernetesClusterRegistry_Status(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster string
+		want    string
+	}{
+		{name: "enabled", cluster: `{"id": "deadbeef-dead-4aa5-beef-deadbeef347d", "registry_enabled": true}`, want: KubernetesRegistryStatusEnabled},
+		{name: "disabled", cluster: `{"id": "deadbeef-dead-4aa5-beef-deadbeef347d", "registry_enabled": false}`, want: KubernetesRegistryStatusDisabled},
+		{name: "omitted", cluster: `{"id": "deadbeef-dead-4aa5-beef-deadbeef347d"}`, want: KubernetesRegistryStatusDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			kubeSvc := client.Kubernetes
+
+			mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodGet)
+				fmt.Fprintf(w, `{"kubernetes_cluster": %s}`, tt.cluster)
+			})
+
+			got, _, err := kubeSvc.RegistryStatus(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesClusters_IsRegistryEnabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/deadbeef-dead-4aa5-beef-deadbeef347d", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "deadbeef-dead-4aa5-beef-deadbeef347d", "registry_enabled": true}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+
+	enabled, _, err := kubeSvc.IsRegistryEnabled(ctx, "deadbeef-dead-4aa5-beef-deadbeef347d")
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	enabled, _, err = kubeSvc.IsRegistryEnabled(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+func TestKubernetesClusters_ListRegistryIntegratedClusters(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_clusters": [
+			{"id": "deadbeef-dead-4aa5-beef-deadbeef347d", "registry_enabled": true},
+			{"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "registry_enabled": false},
+			{"id": "1fd32a6e-2a2b-4a7b-9f5e-deadbeef0001"}
+		]}`)
+	})
+
+	got, _, err := kubeSvc.ListRegistryIntegratedClusters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deadbeef-dead-4aa5-beef-deadbeef347d"}, got)
+}
+
 func TestKubernetesRunClusterlint_WithRequestBody(t *testing.T) {
 	setup()
 	defer teardown()
@@ -1877,6 +3667,250 @@ func TestKubernetesGetClusterlint_WithoutRunID(t *testing.T) {
 
 }
 
+func TestKubernetesGetLatestClusterlintResults(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+	jBlob := `
+{
+	"run_id": "1234",
+	"diagnostics": [
+		{
+			"check_name": "unused-config-map",
+			"severity": "warning",
+			"message": "Unused config map"
+		}
+	]
+}`
+
+	expected := []*ClusterlintDiagnostic{
+		{
+			CheckName: "unused-config-map",
+			Severity:  "warning",
+			Message:   "Unused config map",
+		},
+	}
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/clusterlint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		require.Equal(t, "", r.URL.RawQuery)
+		fmt.Fprint(w, jBlob)
+	})
+
+	diagnostics, _, err := kubeSvc.GetLatestClusterlintResults(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.Equal(t, expected, diagnostics)
+
+	diagnostics, _, err = kubeSvc.GetClusterlintResults(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, diagnostics)
+}
+
+func TestParseTaint(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Taint
+		wantErr bool
+	}{
+		{in: "key=value:NoSchedule", want: Taint{Key: "key", Value: "value", Effect: "NoSchedule"}},
+		{in: "dedicated:PreferNoSchedule", want: Taint{Key: "dedicated", Effect: "PreferNoSchedule"}},
+		{in: "example.com/gpu=true:NoExecute", want: Taint{Key: "example.com/gpu", Value: "true", Effect: "NoExecute"}},
+		{in: "key=:NoSchedule", want: Taint{Key: "key", Effect: "NoSchedule"}},
+		{in: "key=value", wantErr: true},
+		{in: "key=value:Sometimes", wantErr: true},
+		{in: "=value:NoSchedule", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTaint(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTaint_RoundTrip(t *testing.T) {
+	for _, taint := range []Taint{
+		{Key: "key", Value: "value", Effect: "NoSchedule"},
+		{Key: "key", Effect: "NoExecute"},
+	} {
+		got, err := ParseTaint(taint.String())
+		require.NoError(t, err)
+		assert.Equal(t, taint, got)
+	}
+}
+
+func TestNodePoolCreateRequestWithTaintStrings(t *testing.T) {
+	req := &KubernetesNodePoolCreateRequest{
+		Name:   "pool-a",
+		Taints: []Taint{{Key: "existing", Effect: "NoSchedule"}},
+	}
+
+	err := NodePoolCreateRequestWithTaintStrings(req, []string{"key=value:NoSchedule", "dedicated:NoExecute"})
+	require.NoError(t, err)
+	assert.Equal(t, []Taint{
+		{Key: "existing", Effect: "NoSchedule"},
+		{Key: "key", Value: "value", Effect: "NoSchedule"},
+		{Key: "dedicated", Effect: "NoExecute"},
+	}, req.Taints)
+
+	err = NodePoolCreateRequestWithTaintStrings(req, []string{"ok:NoSchedule", "bad", "worse:Never"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"bad"`)
+	assert.Contains(t, err.Error(), `"worse:Never"`)
+	assert.Len(t, req.Taints, 3, "taints must not change on error")
+
+	require.Error(t, NodePoolCreateRequestWithTaintStrings(nil, []string{"key:NoSchedule"}))
+}
+
+func TestKubernetesNode_IsRecoverable(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *KubernetesNodeStatus
+		want   bool
+	}{
+		{
+			name:   "no status",
+			status: nil,
+			want:   true,
+		},
+		{
+			name:   "running",
+			status: &KubernetesNodeStatus{State: "running"},
+			want:   true,
+		},
+		{
+			name:   "still provisioning",
+			status: &KubernetesNodeStatus{State: "error", Message: "droplet is still provisioning"},
+			want:   true,
+		},
+		{
+			name:   "timed out",
+			status: &KubernetesNodeStatus{State: "error", Message: "timed out waiting for node to join"},
+			want:   true,
+		},
+		{
+			name:   "droplet quota",
+			status: &KubernetesNodeStatus{State: "error", Message: "Droplet Quota exceeded for the account"},
+			want:   false,
+		},
+		{
+			name:   "size unavailable",
+			status: &KubernetesNodeStatus{State: "error", Message: "size is not available in this region"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &KubernetesNode{Status: tt.status}
+			assert.Equal(t, tt.want, node.IsRecoverable())
+		})
+	}
+}
+
+func TestKubernetesNode_ReadyLatency(t *testing.T) {
+	created := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		node   *KubernetesNode
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name: "running",
+			node: &KubernetesNode{
+				Status:    &KubernetesNodeStatus{State: "running"},
+				CreatedAt: created,
+				UpdatedAt: created.Add(3*time.Minute + 20*time.Second),
+			},
+			want:   3*time.Minute + 20*time.Second,
+			wantOK: true,
+		},
+		{
+			name: "provisioning",
+			node: &KubernetesNode{
+				Status:    &KubernetesNodeStatus{State: "provisioning"},
+				CreatedAt: created,
+				UpdatedAt: created.Add(time.Minute),
+			},
+		},
+		{
+			name: "no status",
+			node: &KubernetesNode{CreatedAt: created, UpdatedAt: created.Add(time.Minute)},
+		},
+		{
+			name: "missing timestamps",
+			node: &KubernetesNode{Status: &KubernetesNodeStatus{State: "running"}},
+		},
+		{
+			name: "updated before created",
+			node: &KubernetesNode{
+				Status:    &KubernetesNodeStatus{State: "running"},
+				CreatedAt: created,
+				UpdatedAt: created.Add(-time.Minute),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.node.ReadyLatency()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesClusterAutoscalerConfiguration(t *testing.T) {
+	var unset *KubernetesClusterAutoscalerConfiguration
+	assert.Equal(t, 0.5, unset.EffectiveThreshold())
+	d, err := unset.ScaleDownUnneededDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, d)
+
+	empty := &KubernetesClusterAutoscalerConfiguration{}
+	assert.Equal(t, 0.5, empty.EffectiveThreshold())
+	d, err = empty.ScaleDownUnneededDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, d)
+
+	set := &KubernetesClusterAutoscalerConfiguration{
+		ScaleDownUtilizationThreshold: PtrTo(0.65),
+		ScaleDownUnneededTime:         PtrTo("1m30s"),
+	}
+	assert.Equal(t, 0.65, set.EffectiveThreshold())
+	d, err = set.ScaleDownUnneededDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	invalid := &KubernetesClusterAutoscalerConfiguration{ScaleDownUnneededTime: PtrTo("90 seconds")}
+	_, err = invalid.ScaleDownUnneededDuration()
+	require.Error(t, err)
+}
+
+func TestKubernetesClusterAutoscalerConfiguration_Decode(t *testing.T) {
+	jBlob := `
+{
+	"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+	"cluster_autoscaler_configuration": {
+		"scale_down_utilization_threshold": 0.2,
+		"scale_down_unneeded_time": "1m0s"
+	}
+}`
+	cluster := new(KubernetesCluster)
+	require.NoError(t, json.Unmarshal([]byte(jBlob), cluster))
+	assert.Equal(t, &KubernetesClusterAutoscalerConfiguration{
+		ScaleDownUtilizationThreshold: PtrTo(0.2),
+		ScaleDownUnneededTime:         PtrTo("1m0s"),
+	}, cluster.ClusterAutoscalerConfiguration)
+}
+
 var maintenancePolicyDayTests = []struct {
 	name  string
 	json  string
@@ -0,0 +1,214 @@
+package godo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// kubernetesMaintenanceStartTimeLayout is the layout of
+// KubernetesMaintenancePolicy.StartTime.
+const kubernetesMaintenanceStartTimeLayout = "15:04"
+
+// dnsLabelRegexp matches an RFC 1123 DNS label of up to 63 characters.
+var dnsLabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// uuidRegexp matches a UUID in its canonical, hyphenated form.
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Validate checks the cluster create request for mistakes that would make the
+// API reject it: a missing name, region or version, no node pools, a node pool
+// that would have no nodes, malformed or overlapping cluster and service
+// subnets, or an invalid address in an enabled control plane firewall. All
+// problems are reported at once, joined in a single error.
+func (r *KubernetesClusterCreateRequest) Validate() error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, NewArgError("Name", "cannot be an empty string"))
+	}
+	if r.RegionSlug == "" {
+		errs = append(errs, NewArgError("RegionSlug", "cannot be an empty string"))
+	}
+	if r.VersionSlug == "" {
+		errs = append(errs, NewArgError("VersionSlug", "cannot be an empty string"))
+	}
+	if len(r.NodePools) == 0 {
+		errs = append(errs, NewArgError("NodePools", "cannot be empty"))
+	}
+	for i, pool := range r.NodePools {
+		if pool == nil {
+			errs = append(errs, NewArgError(fmt.Sprintf("NodePools[%d]", i), "cannot be nil"))
+			continue
+		}
+		if pool.Count == 0 && !pool.AutoScale {
+			errs = append(errs, NewArgError(fmt.Sprintf("NodePools[%d].Count", i), "cannot be 0 when autoscaling is disabled"))
+		}
+		if err := pool.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("NodePools[%d]: %w", i, err))
+		}
+	}
+	if r.MaintenancePolicy != nil {
+		if err := r.MaintenancePolicy.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("MaintenancePolicy: %w", err))
+		}
+	}
+	if err := validateKubernetesSubnets(r.ClusterSubnet, r.ServiceSubnet); err != nil {
+		errs = append(errs, err)
+	}
+	if r.ControlPlaneFirewall.isEnabled() {
+		if err := r.ControlPlaneFirewall.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ControlPlaneFirewall: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the node pool create request for inconsistencies that the
+// API would reject. For autoscaling pools, MinNodes must not exceed MaxNodes
+// and a non-zero Count must lie within [MinNodes, MaxNodes]. The returned
+// error is an *ArgError naming the offending field.
+func (r *KubernetesNodePoolCreateRequest) Validate() error {
+	if r.Count < 0 {
+		return NewArgError("Count", "cannot be less than 0")
+	}
+	if !r.AutoScale {
+		return nil
+	}
+	if r.MinNodes < 0 {
+		return NewArgError("MinNodes", "cannot be less than 0")
+	}
+	if r.MinNodes > r.MaxNodes {
+		return NewArgError("MinNodes", "cannot be greater than MaxNodes")
+	}
+	if r.Count != 0 && (r.Count < r.MinNodes || r.Count > r.MaxNodes) {
+		return NewArgError("Count", "must be between MinNodes and MaxNodes")
+	}
+	return nil
+}
+
+// Validate checks that StartTime is an HH:MM time, that Duration, if set, is a
+// valid Go duration, and that Day is a known day. Duration is usually left
+// empty in requests, as it is chosen by the API.
+func (p *KubernetesMaintenancePolicy) Validate() error {
+	if _, err := time.Parse(kubernetesMaintenanceStartTimeLayout, p.StartTime); err != nil {
+		return NewArgError("StartTime", fmt.Sprintf("%q is not in HH:MM format", p.StartTime))
+	}
+	if p.Duration != "" {
+		if _, err := time.ParseDuration(p.Duration); err != nil {
+			return NewArgError("Duration", fmt.Sprintf("%q is not a valid duration", p.Duration))
+		}
+	}
+	if p.Day < KubernetesMaintenanceDayAny || p.Day > KubernetesMaintenanceDaySunday {
+		return NewArgError("Day", fmt.Sprintf("%d is not a valid day", p.Day))
+	}
+	return nil
+}
+
+// Validate checks that the registry request names at least one cluster and
+// that every entry of ClusterUUIDs is a UUID. The returned error is an
+// *ArgError listing all malformed UUIDs.
+func (r *KubernetesClusterRegistryRequest) Validate() error {
+	if r == nil || len(r.ClusterUUIDs) == 0 {
+		return NewArgError("ClusterUUIDs", "cannot be empty")
+	}
+	var invalid []string
+	for _, id := range r.ClusterUUIDs {
+		if !uuidRegexp.MatchString(id) {
+			invalid = append(invalid, fmt.Sprintf("%q", id))
+		}
+	}
+	if len(invalid) > 0 {
+		return NewArgError("ClusterUUIDs", "contains invalid UUIDs: "+strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// validateKubernetesSubnets checks that the cluster and service subnets are
+// CIDR blocks that do not overlap. Empty subnets are left to the API to choose.
+func validateKubernetesSubnets(clusterSubnet, serviceSubnet string) error {
+	var cluster, service *net.IPNet
+	if clusterSubnet != "" {
+		_, n, err := net.ParseCIDR(clusterSubnet)
+		if err != nil {
+			return NewArgError("ClusterSubnet", fmt.Sprintf("%q is not a valid CIDR block", clusterSubnet))
+		}
+		cluster = n
+	}
+	if serviceSubnet != "" {
+		_, n, err := net.ParseCIDR(serviceSubnet)
+		if err != nil {
+			return NewArgError("ServiceSubnet", fmt.Sprintf("%q is not a valid CIDR block", serviceSubnet))
+		}
+		service = n
+	}
+	if cluster != nil && service != nil && (cluster.Contains(service.IP) || service.Contains(cluster.IP)) {
+		return NewArgError("ServiceSubnet", fmt.Sprintf("%s overlaps with ClusterSubnet %s", serviceSubnet, clusterSubnet))
+	}
+	return nil
+}
+
+// Validate checks that every entry of AllowedAddresses is a CIDR block or a
+// bare IPv4 or IPv6 address. The returned error is an *ArgError naming the
+// first invalid address.
+func (f *KubernetesControlPlaneFirewall) Validate() error {
+	for _, addr := range f.AllowedAddresses {
+		if net.ParseIP(addr) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(addr); err != nil {
+			return NewArgError("AllowedAddresses", fmt.Sprintf("%q is not a valid IP address or CIDR block", addr))
+		}
+	}
+	return nil
+}
+
+// isEnabled reports whether the firewall is set and enabled.
+func (f *KubernetesControlPlaneFirewall) isEnabled() bool {
+	return f != nil && f.Enabled != nil && *f.Enabled
+}
+
+// IsDNSCompatibleName reports whether the cluster name is a valid DNS label:
+// at most 63 lowercase letters, digits or hyphens, starting and ending with a
+// letter or digit. Names that are not can still be used for clusters, but not
+// to derive hostnames, e.g. for ingresses.
+func (r *KubernetesClusterCreateRequest) IsDNSCompatibleName() bool {
+	return dnsLabelRegexp.MatchString(r.Name)
+}
+
+// Validate checks that the groups and checks the clusterlint request filters
+// on are listed in known, such as the catalog returned by
+// GetClusterlintCatalog, as the API silently ignores unknown names. The
+// returned error joins an *ArgError for every field with unknown names.
+func (r *KubernetesRunClusterlintRequest) Validate(known KubernetesClusterlintCatalog) error {
+	if r == nil {
+		return nil
+	}
+	return errors.Join(
+		validateClusterlintNames("IncludeGroups", r.IncludeGroups, known.Groups),
+		validateClusterlintNames("ExcludeGroups", r.ExcludeGroups, known.Groups),
+		validateClusterlintNames("IncludeChecks", r.IncludeChecks, known.Checks),
+		validateClusterlintNames("ExcludeChecks", r.ExcludeChecks, known.Checks),
+	)
+}
+
+// validateClusterlintNames returns an *ArgError for field listing the names
+// that are not in known, or nil if there are none.
+func validateClusterlintNames(field string, names, known []string) error {
+	knownSet := make(map[string]bool, len(known))
+	for _, name := range known {
+		knownSet[name] = true
+	}
+	var unknown []string
+	for _, name := range names {
+		if !knownSet[name] {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) > 0 {
+		return NewArgError(field, "contains unknown names: "+strings.Join(unknown, ", "))
+	}
+	return nil
+}
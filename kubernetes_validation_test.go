@@ -0,0 +1,514 @@
+package godo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesNodePoolCreateRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *KubernetesNodePoolCreateRequest
+		wantErr error
+	}{
+		{
+			name: "fixed size pool",
+			req:  &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", Count: 3},
+		},
+		{
+			name: "autoscaling pool",
+			req:  &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5},
+		},
+		{
+			name: "autoscaling pool without count",
+			req:  &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", AutoScale: true, MinNodes: 1, MaxNodes: 5},
+		},
+		{
+			name:    "negative count",
+			req:     &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", Count: -1},
+			wantErr: NewArgError("Count", "cannot be less than 0"),
+		},
+		{
+			name:    "negative min",
+			req:     &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", AutoScale: true, MinNodes: -1, MaxNodes: 5},
+			wantErr: NewArgError("MinNodes", "cannot be less than 0"),
+		},
+		{
+			name:    "min above max",
+			req:     &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", AutoScale: true, MinNodes: 6, MaxNodes: 5},
+			wantErr: NewArgError("MinNodes", "cannot be greater than MaxNodes"),
+		},
+		{
+			name:    "count below min",
+			req:     &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", Count: 1, AutoScale: true, MinNodes: 2, MaxNodes: 5},
+			wantErr: NewArgError("Count", "must be between MinNodes and MaxNodes"),
+		},
+		{
+			name:    "count above max",
+			req:     &KubernetesNodePoolCreateRequest{Name: "pool", Size: "s-1vcpu-2gb", Count: 6, AutoScale: true, MinNodes: 2, MaxNodes: 5},
+			wantErr: NewArgError("Count", "must be between MinNodes and MaxNodes"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestKubernetesClusters_CreateNodePool_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for an invalid node pool")
+	})
+
+	_, _, err := kubeSvc.CreateNodePool(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesNodePoolCreateRequest{
+		Name:      "pool",
+		Size:      "s-1vcpu-2gb",
+		AutoScale: true,
+		MinNodes:  6,
+		MaxNodes:  5,
+	})
+	require.Equal(t, NewArgError("MinNodes", "cannot be greater than MaxNodes"), err)
+}
+
+func TestKubernetesClusterCreateRequest_Validate(t *testing.T) {
+	valid := func() *KubernetesClusterCreateRequest {
+		return &KubernetesClusterCreateRequest{
+			Name:        "antoine",
+			RegionSlug:  "nyc1",
+			VersionSlug: "1.30.2-do.0",
+			NodePools: []*KubernetesNodePoolCreateRequest{
+				{Name: "pool-a", Size: "s-1vcpu-2gb", Count: 3},
+				{Name: "pool-b", Size: "s-1vcpu-2gb", AutoScale: true, MinNodes: 0, MaxNodes: 3},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*KubernetesClusterCreateRequest)
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			mutate: func(*KubernetesClusterCreateRequest) {},
+		},
+		{
+			name:    "empty name",
+			mutate:  func(r *KubernetesClusterCreateRequest) { r.Name = "" },
+			wantErr: NewArgError("Name", "cannot be an empty string"),
+		},
+		{
+			name:    "empty region",
+			mutate:  func(r *KubernetesClusterCreateRequest) { r.RegionSlug = "" },
+			wantErr: NewArgError("RegionSlug", "cannot be an empty string"),
+		},
+		{
+			name:    "empty version",
+			mutate:  func(r *KubernetesClusterCreateRequest) { r.VersionSlug = "" },
+			wantErr: NewArgError("VersionSlug", "cannot be an empty string"),
+		},
+		{
+			name:    "no node pools",
+			mutate:  func(r *KubernetesClusterCreateRequest) { r.NodePools = nil },
+			wantErr: NewArgError("NodePools", "cannot be empty"),
+		},
+		{
+			name: "invalid maintenance policy",
+			mutate: func(r *KubernetesClusterCreateRequest) {
+				r.MaintenancePolicy = &KubernetesMaintenancePolicy{StartTime: "4pm"}
+			},
+			wantErr: NewArgError("StartTime", `"4pm" is not in HH:MM format`),
+		},
+		{
+			name:    "node pool without nodes",
+			mutate:  func(r *KubernetesClusterCreateRequest) { r.NodePools[0].Count = 0 },
+			wantErr: NewArgError("NodePools[0].Count", "cannot be 0 when autoscaling is disabled"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(req)
+			err := req.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr.Error())
+		})
+	}
+}
+
+func TestKubernetesClusterCreateRequest_Validate_ReportsAllProblems(t *testing.T) {
+	err := (&KubernetesClusterCreateRequest{}).Validate()
+	require.Error(t, err)
+
+	for _, want := range []error{
+		NewArgError("Name", "cannot be an empty string"),
+		NewArgError("RegionSlug", "cannot be an empty string"),
+		NewArgError("VersionSlug", "cannot be an empty string"),
+		NewArgError("NodePools", "cannot be empty"),
+	} {
+		require.ErrorContains(t, err, want.Error())
+	}
+}
+
+func TestKubernetesClusters_Create_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for an invalid cluster")
+	})
+
+	_, _, err := kubeSvc.Create(ctx, &KubernetesClusterCreateRequest{Name: "antoine"})
+	require.ErrorContains(t, err, NewArgError("RegionSlug", "cannot be an empty string").Error())
+}
+
+func TestKubernetesMaintenancePolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *KubernetesMaintenancePolicy
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			policy: &KubernetesMaintenancePolicy{StartTime: "04:30", Duration: "4h0m0s", Day: KubernetesMaintenanceDaySunday},
+		},
+		{
+			name:   "valid without duration",
+			policy: &KubernetesMaintenancePolicy{StartTime: "00:00", Day: KubernetesMaintenanceDayAny},
+		},
+		{
+			name:    "empty start time",
+			policy:  &KubernetesMaintenancePolicy{Day: KubernetesMaintenanceDayMonday},
+			wantErr: NewArgError("StartTime", `"" is not in HH:MM format`),
+		},
+		{
+			name:    "start time out of range",
+			policy:  &KubernetesMaintenancePolicy{StartTime: "25:00", Day: KubernetesMaintenanceDayMonday},
+			wantErr: NewArgError("StartTime", `"25:00" is not in HH:MM format`),
+		},
+		{
+			name:    "start time with seconds",
+			policy:  &KubernetesMaintenancePolicy{StartTime: "04:00:00", Day: KubernetesMaintenanceDayMonday},
+			wantErr: NewArgError("StartTime", `"04:00:00" is not in HH:MM format`),
+		},
+		{
+			name:    "malformed duration",
+			policy:  &KubernetesMaintenancePolicy{StartTime: "04:00", Duration: "4 hours", Day: KubernetesMaintenanceDayMonday},
+			wantErr: NewArgError("Duration", `"4 hours" is not a valid duration`),
+		},
+		{
+			name:    "invalid day",
+			policy:  &KubernetesMaintenancePolicy{StartTime: "04:00", Day: 100},
+			wantErr: NewArgError("Day", "100 is not a valid day"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestKubernetesClusters_Update_InvalidMaintenancePolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for an invalid maintenance policy")
+	})
+
+	_, _, err := kubeSvc.Update(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesClusterUpdateRequest{
+		MaintenancePolicy: &KubernetesMaintenancePolicy{StartTime: "4pm"},
+	})
+	require.Equal(t, NewArgError("StartTime", `"4pm" is not in HH:MM format`), err)
+}
+
+func TestKubernetesClusterCreateRequest_IsDNSCompatibleName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "prod-cluster-1", want: true},
+		{name: "a", want: true},
+		{name: "k8s", want: true},
+		{name: strings.Repeat("a", 63), want: true},
+		{name: strings.Repeat("a", 64), want: false},
+		{name: "", want: false},
+		{name: "Prod-Cluster", want: false},
+		{name: "prod_cluster", want: false},
+		{name: "-prod", want: false},
+		{name: "prod-", want: false},
+		{name: "prod.cluster", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &KubernetesClusterCreateRequest{Name: tt.name}
+			require.Equal(t, tt.want, req.IsDNSCompatibleName())
+		})
+	}
+}
+
+func TestKubernetesClusterRegistryRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *KubernetesClusterRegistryRequest
+		want error
+	}{
+		{
+			name: "valid",
+			req:  &KubernetesClusterRegistryRequest{ClusterUUIDs: []string{"8d91899c-0739-4a1a-acc5-deadbeefbb8f", "DEADBEEF-DEAD-4AA5-BEEF-DEADBEEF347D"}},
+		},
+		{
+			name: "nil",
+			want: NewArgError("ClusterUUIDs", "cannot be empty"),
+		},
+		{
+			name: "empty list",
+			req:  &KubernetesClusterRegistryRequest{ClusterUUIDs: []string{}},
+			want: NewArgError("ClusterUUIDs", "cannot be empty"),
+		},
+		{
+			name: "not a UUID",
+			req:  &KubernetesClusterRegistryRequest{ClusterUUIDs: []string{"8d91899c-0739-4a1a-acc5-deadbeefbb8f", "my-cluster", ""}},
+			want: NewArgError("ClusterUUIDs", `contains invalid UUIDs: "my-cluster", ""`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.req.Validate())
+		})
+	}
+}
+
+func TestKubernetesClusters_AddRegistry_Invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/registry", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("unexpected request for an invalid registry request")
+	})
+
+	_, err := kubeSvc.AddRegistry(ctx, &KubernetesClusterRegistryRequest{ClusterUUIDs: []string{"my-cluster"}})
+	require.Equal(t, NewArgError("ClusterUUIDs", `contains invalid UUIDs: "my-cluster"`), err)
+
+	_, err = kubeSvc.RemoveRegistry(ctx, &KubernetesClusterRegistryRequest{})
+	require.Equal(t, NewArgError("ClusterUUIDs", "cannot be empty"), err)
+}
+
+func TestKubernetesControlPlaneFirewall_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []string
+		want      error
+	}{
+		{name: "IPv4 CIDR", addresses: []string{"1.2.3.4/32", "10.0.0.0/8"}},
+		{name: "IPv6 CIDR", addresses: []string{"2001:db8::/32"}},
+		{name: "bare IPv4", addresses: []string{"1.2.3.4"}},
+		{name: "bare IPv6", addresses: []string{"2001:db8::1"}},
+		{name: "empty", addresses: nil},
+		{
+			name:      "garbage",
+			addresses: []string{"1.2.3.4/32", "office", "1.2.3.4/33"},
+			want:      NewArgError("AllowedAddresses", `"office" is not a valid IP address or CIDR block`),
+		},
+		{
+			name:      "invalid prefix",
+			addresses: []string{"1.2.3.4/33"},
+			want:      NewArgError("AllowedAddresses", `"1.2.3.4/33" is not a valid IP address or CIDR block`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &KubernetesControlPlaneFirewall{Enabled: PtrTo(true), AllowedAddresses: tt.addresses}
+			require.Equal(t, tt.want, f.Validate())
+		})
+	}
+}
+
+func TestKubernetesClusters_Update_InvalidControlPlaneFirewall(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	requests := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+
+	_, _, err := kubeSvc.Update(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesClusterUpdateRequest{
+		ControlPlaneFirewall: &KubernetesControlPlaneFirewall{Enabled: PtrTo(true), AllowedAddresses: []string{"office"}},
+	})
+	require.Equal(t, NewArgError("AllowedAddresses", `"office" is not a valid IP address or CIDR block`), err)
+	require.Equal(t, 0, requests)
+
+	// A disabled firewall is not checked.
+	_, _, err = kubeSvc.Update(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesClusterUpdateRequest{
+		ControlPlaneFirewall: &KubernetesControlPlaneFirewall{Enabled: PtrTo(false), AllowedAddresses: []string{"office"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+}
+
+func TestKubernetesClusterCreateRequest_Validate_ControlPlaneFirewall(t *testing.T) {
+	req := &KubernetesClusterCreateRequest{
+		Name:        "cluster",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.31.1-do.0",
+		NodePools:   []*KubernetesNodePoolCreateRequest{{Name: "pool", Size: "s-1vcpu-2gb", Count: 1}},
+		ControlPlaneFirewall: &KubernetesControlPlaneFirewall{
+			Enabled:          PtrTo(true),
+			AllowedAddresses: []string{"2001:db8::/129"},
+		},
+	}
+	err := req.Validate()
+	require.ErrorContains(t, err, "ControlPlaneFirewall")
+	require.ErrorContains(t, err, "2001:db8::/129")
+}
+
+func TestKubernetesClusterCreateRequest_Validate_Subnets(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterSubnet string
+		serviceSubnet string
+		want          error
+	}{
+		{name: "defaults"},
+		{name: "cluster subnet only", clusterSubnet: "192.168.0.0/20"},
+		{name: "service subnet only", serviceSubnet: "192.168.16.0/22"},
+		{name: "disjoint", clusterSubnet: "192.168.0.0/20", serviceSubnet: "192.168.16.0/22"},
+		{
+			name:          "overlapping",
+			clusterSubnet: "10.244.0.0/16",
+			serviceSubnet: "10.244.128.0/20",
+			want:          NewArgError("ServiceSubnet", "10.244.128.0/20 overlaps with ClusterSubnet 10.244.0.0/16"),
+		},
+		{
+			name:          "service contains cluster",
+			clusterSubnet: "10.244.16.0/20",
+			serviceSubnet: "10.0.0.0/8",
+			want:          NewArgError("ServiceSubnet", "10.0.0.0/8 overlaps with ClusterSubnet 10.244.16.0/20"),
+		},
+		{
+			name:          "malformed mask",
+			clusterSubnet: "192.168.0.0/33",
+			serviceSubnet: "192.168.16.0/22",
+			want:          NewArgError("ClusterSubnet", `"192.168.0.0/33" is not a valid CIDR block`),
+		},
+		{
+			name:          "bare IP",
+			clusterSubnet: "192.168.0.0/20",
+			serviceSubnet: "192.168.16.1",
+			want:          NewArgError("ServiceSubnet", `"192.168.16.1" is not a valid CIDR block`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &KubernetesClusterCreateRequest{
+				Name:          "cluster",
+				RegionSlug:    "nyc1",
+				VersionSlug:   "1.31.1-do.0",
+				NodePools:     []*KubernetesNodePoolCreateRequest{{Name: "pool", Size: "s-1vcpu-2gb", Count: 1}},
+				ClusterSubnet: tt.clusterSubnet,
+				ServiceSubnet: tt.serviceSubnet,
+			}
+			err := req.Validate()
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.want.Error())
+		})
+	}
+}
+
+func TestKubernetesRunClusterlintRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *KubernetesRunClusterlintRequest
+		wantErr string
+	}{
+		{
+			name: "known names",
+			req: &KubernetesRunClusterlintRequest{
+				IncludeGroups: []string{"basic", "doks"},
+				ExcludeChecks: []string{"unused-config-map"},
+			},
+		},
+		{
+			name: "empty",
+			req:  &KubernetesRunClusterlintRequest{},
+		},
+		{
+			name: "nil",
+		},
+		{
+			name: "unknown check",
+			req: &KubernetesRunClusterlintRequest{
+				IncludeGroups: []string{"basic"},
+				ExcludeChecks: []string{"unused-config-map", "unused-configmap"},
+			},
+			wantErr: NewArgError("ExcludeChecks", `contains unknown names: "unused-configmap"`).Error(),
+		},
+		{
+			name: "unknown group and check",
+			req: &KubernetesRunClusterlintRequest{
+				IncludeGroups: []string{"securty"},
+				IncludeChecks: []string{"latest-tags"},
+			},
+			wantErr: NewArgError("IncludeGroups", `contains unknown names: "securty"`).Error() + "\n" +
+				NewArgError("IncludeChecks", `contains unknown names: "latest-tags"`).Error(),
+		},
+		{
+			name: "check name used as group",
+			req: &KubernetesRunClusterlintRequest{
+				ExcludeGroups: []string{"latest-tag"},
+			},
+			wantErr: NewArgError("ExcludeGroups", `contains unknown names: "latest-tag"`).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate(KnownClusterlintCatalog())
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
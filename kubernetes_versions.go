@@ -0,0 +1,277 @@
+package godo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kubernetesSemver is a parsed DigitalOcean Kubernetes version such as
+// 1.31.1-do.0.
+type kubernetesSemver struct {
+	major, minor, patch int
+
+	// revision is the N of the -do.N suffix.
+	revision int
+}
+
+// parseKubernetesSemver parses a version slug of the form
+// MAJOR.MINOR.PATCH[-do.N]. A leading "v" is accepted.
+func parseKubernetesSemver(s string) (kubernetesSemver, error) {
+	var v kubernetesSemver
+
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.Index(core, "-do."); i >= 0 {
+		rev, err := strconv.Atoi(core[i+len("-do."):])
+		if err != nil || rev < 0 {
+			return v, fmt.Errorf("invalid Kubernetes version %q", s)
+		}
+		v.revision = rev
+		core = core[:i]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid Kubernetes version %q", s)
+	}
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid Kubernetes version %q", s)
+		}
+		nums[i] = n
+	}
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 depending on whether v is older than, equal to
+// or newer than o.
+func (v kubernetesSemver) compare(o kubernetesSemver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch, v.revision - o.revision} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// semver parses the version. The slug is preferred as it carries the -do.N
+// revision; the upstream Kubernetes version is used if the slug is empty.
+func (v *KubernetesVersion) semver() (kubernetesSemver, error) {
+	if v.Slug != "" {
+		return parseKubernetesSemver(v.Slug)
+	}
+	return parseKubernetesSemver(v.KubernetesVersion)
+}
+
+// LatestVersion returns the newest of the available Kubernetes versions,
+// comparing them by version number and then by their -do.N revision. Versions
+// that cannot be parsed are ignored.
+func (o *KubernetesOptions) LatestVersion() (*KubernetesVersion, error) {
+	if len(o.Versions) == 0 {
+		return nil, errors.New("no Kubernetes versions available")
+	}
+
+	var (
+		latest       *KubernetesVersion
+		latestSemver kubernetesSemver
+	)
+	for _, v := range o.Versions {
+		if v == nil {
+			continue
+		}
+		sv, err := v.semver()
+		if err != nil {
+			continue
+		}
+		if latest == nil || sv.compare(latestSemver) > 0 {
+			latest, latestSemver = v, sv
+		}
+	}
+	if latest == nil {
+		return nil, errors.New("no valid Kubernetes versions available")
+	}
+	return latest, nil
+}
+
+// RecommendedUpgrade returns the safest version to upgrade to from the
+// version with the current slug, such as a cluster's VersionSlug, among
+// candidates, such as the versions returned by GetUpgrades. It prefers the
+// newest release of the next minor version and otherwise falls back to the
+// newest release of the current minor version, comparing releases by patch
+// number and -do.N revision. Versions that skip a minor version are never
+// recommended, as Kubernetes only supports upgrading one minor version at a
+// time. Candidates that cannot be parsed are ignored.
+func RecommendedUpgrade(current string, candidates []*KubernetesVersion) (*KubernetesVersion, error) {
+	cur, err := parseKubernetesSemver(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		nextMinor, sameMinor             *KubernetesVersion
+		nextMinorSemver, sameMinorSemver kubernetesSemver
+	)
+	for _, v := range candidates {
+		if v == nil {
+			continue
+		}
+		sv, err := v.semver()
+		if err != nil || sv.major != cur.major || sv.compare(cur) <= 0 {
+			continue
+		}
+		switch sv.minor {
+		case cur.minor + 1:
+			if nextMinor == nil || sv.compare(nextMinorSemver) > 0 {
+				nextMinor, nextMinorSemver = v, sv
+			}
+		case cur.minor:
+			if sameMinor == nil || sv.compare(sameMinorSemver) > 0 {
+				sameMinor, sameMinorSemver = v, sv
+			}
+		}
+	}
+	if nextMinor != nil {
+		return nextMinor, nil
+	}
+	if sameMinor != nil {
+		return sameMinor, nil
+	}
+	return nil, fmt.Errorf("no supported upgrade from Kubernetes version %q", current)
+}
+
+// NodesOnOldVersion returns the nodes of the cluster whose kubelet runs an
+// older version than controlPlaneVersion, such as the cluster's VersionSlug.
+// Only the MAJOR.MINOR.PATCH part of the versions is compared, as kubelets do
+// not report the -do.N revision. Nodes without a known kubelet version are
+// skipped. It returns false if no node reports a kubelet version, which is
+// the case with the API today, or if controlPlaneVersion cannot be parsed:
+// the version skew is then unknown, not absent.
+func (kc *KubernetesCluster) NodesOnOldVersion(controlPlaneVersion string) ([]*KubernetesNode, bool) {
+	want, err := parseKubernetesSemver(controlPlaneVersion)
+	if err != nil {
+		return nil, false
+	}
+	want.revision = 0
+
+	var old []*KubernetesNode
+	known := false
+	for _, pool := range kc.NodePools {
+		for _, node := range pool.Nodes {
+			if node == nil || node.KubeletVersion == "" {
+				continue
+			}
+			have, err := parseKubernetesSemver(node.KubeletVersion)
+			if err != nil {
+				continue
+			}
+			known = true
+			have.revision = 0
+			if have.compare(want) < 0 {
+				old = append(old, node)
+			}
+		}
+	}
+	return old, known
+}
+
+// VersionsInRegion returns the versions known to be available in the region
+// with the given slug. It returns false if no version lists its regions, in
+// which case availability is unknown rather than universal; the API does not
+// report the regions of versions today. Versions that do not list their
+// regions are left out.
+func (o *KubernetesOptions) VersionsInRegion(region string) ([]*KubernetesVersion, bool) {
+	var versions []*KubernetesVersion
+	known := false
+	for _, v := range o.Versions {
+		if v == nil || len(v.Regions) == 0 {
+			continue
+		}
+		known = true
+		for _, r := range v.Regions {
+			if r == region {
+				versions = append(versions, v)
+				break
+			}
+		}
+	}
+	return versions, known
+}
+
+// Known features that can be listed in KubernetesVersion.SupportedFeatures.
+const (
+	KubernetesFeatureClusterAutoscaler   = "cluster-autoscaler"
+	KubernetesFeatureDOCRIntegration     = "docr-integration"
+	KubernetesFeatureHAControlPlane      = "ha-control-plane"
+	KubernetesFeatureTokenAuthentication = "token-authentication"
+)
+
+// SupportsFeature reports whether the version supports the named feature, such
+// as KubernetesFeatureHAControlPlane.
+func (v *KubernetesVersion) SupportsFeature(name string) bool {
+	for _, f := range v.SupportedFeatures {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// kubernetesSupportDateLayouts are the formats accepted for the dates of a
+// version's support window.
+var kubernetesSupportDateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// SupportWindow returns the start and end of the support window of the
+// version's minor release. It returns false if the API did not report the
+// window, which it does not today, or its dates cannot be parsed.
+func (v *KubernetesVersion) SupportWindow() (start, end time.Time, ok bool) {
+	start, ok = parseKubernetesSupportDate(v.SupportStart)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	end, ok = parseKubernetesSupportDate(v.SupportEnd)
+	if !ok || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// parseKubernetesSupportDate parses a date of a support window.
+func parseKubernetesSupportDate(s string) (time.Time, bool) {
+	for _, layout := range kubernetesSupportDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetClusterSupportedFeatures returns the features supported by the
+// Kubernetes version the cluster runs, as listed in the Kubernetes options.
+func (svc *KubernetesServiceOp) GetClusterSupportedFeatures(ctx context.Context, clusterID string) ([]string, *Response, error) {
+	cluster, resp, err := svc.Get(ctx, clusterID)
+	if err != nil {
+		return nil, resp, err
+	}
+	options, resp, err := svc.GetOptions(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, v := range options.Versions {
+		if v == nil {
+			continue
+		}
+		if v.Slug == cluster.VersionSlug {
+			return v.SupportedFeatures, resp, nil
+		}
+	}
+	return nil, resp, fmt.Errorf("version %q of cluster %s is not listed in the Kubernetes options", cluster.VersionSlug, clusterID)
+}
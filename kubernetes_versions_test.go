@@ -0,0 +1,312 @@
+package godo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKubernetesSemver(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    kubernetesSemver
+		wantErr bool
+	}{
+		{in: "1.31.1-do.0", want: kubernetesSemver{1, 31, 1, 0}},
+		{in: "1.29.10-do.12", want: kubernetesSemver{1, 29, 10, 12}},
+		{in: "v1.30.2", want: kubernetesSemver{1, 30, 2, 0}},
+		{in: "latest", wantErr: true},
+		{in: "1.31", wantErr: true},
+		{in: "1.31.1-do.x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseKubernetesSemver(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKubernetesOptions_LatestVersion(t *testing.T) {
+	opts := &KubernetesOptions{
+		Versions: []*KubernetesVersion{
+			{Slug: "1.29.9-do.3", KubernetesVersion: "1.29.9"},
+			{Slug: "1.31.1-do.0", KubernetesVersion: "1.31.1"},
+			{Slug: "1.30.10-do.0", KubernetesVersion: "1.30.10"},
+			{Slug: "1.31.1-do.2", KubernetesVersion: "1.31.1"},
+			{Slug: "1.31.1-do.10", KubernetesVersion: "1.31.1"},
+			{Slug: "latest", KubernetesVersion: "latest"},
+		},
+	}
+
+	got, err := opts.LatestVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.31.1-do.10", got.Slug)
+}
+
+func TestKubernetesOptions_LatestVersion_NoVersions(t *testing.T) {
+	_, err := (&KubernetesOptions{}).LatestVersion()
+	require.Error(t, err)
+
+	_, err = (&KubernetesOptions{Versions: []*KubernetesVersion{{Slug: "latest"}}}).LatestVersion()
+	require.Error(t, err)
+}
+
+func TestRecommendedUpgrade(t *testing.T) {
+	versions := func(slugs ...string) []*KubernetesVersion {
+		vs := make([]*KubernetesVersion, len(slugs))
+		for i, slug := range slugs {
+			vs[i] = &KubernetesVersion{Slug: slug}
+		}
+		return vs
+	}
+
+	tests := []struct {
+		name       string
+		current    string
+		candidates []*KubernetesVersion
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "highest patch of next minor",
+			current:    "1.30.4-do.0",
+			candidates: versions("1.30.5-do.0", "1.31.1-do.0", "1.31.3-do.0", "1.31.2-do.5"),
+			want:       "1.31.3-do.0",
+		},
+		{
+			name:       "revision breaks ties",
+			current:    "1.30.4-do.0",
+			candidates: versions("1.31.3-do.2", "1.31.3-do.10", "1.31.3-do.9"),
+			want:       "1.31.3-do.10",
+		},
+		{
+			name:       "latest patch of current minor",
+			current:    "1.31.1-do.0",
+			candidates: versions("1.31.1-do.2", "1.31.4-do.0", "1.31.2-do.1"),
+			want:       "1.31.4-do.0",
+		},
+		{
+			name:       "newer revision of current patch",
+			current:    "1.31.1-do.0",
+			candidates: versions("1.31.1-do.1"),
+			want:       "1.31.1-do.1",
+		},
+		{
+			name:       "skips a minor",
+			current:    "1.29.9-do.3",
+			candidates: versions("1.31.1-do.0", "1.30.5-do.0", "1.29.10-do.0"),
+			want:       "1.30.5-do.0",
+		},
+		{
+			name:       "only skip-a-minor candidates",
+			current:    "1.29.9-do.3",
+			candidates: versions("1.31.1-do.0", "1.32.0-do.0"),
+			wantErr:    true,
+		},
+		{
+			name:       "older and invalid candidates are ignored",
+			current:    "1.31.1-do.2",
+			candidates: versions("1.31.1-do.1", "1.30.9-do.0", "latest", "2.0.0-do.0"),
+			wantErr:    true,
+		},
+		{
+			name:       "no candidates",
+			current:    "1.31.1-do.0",
+			candidates: nil,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid current version",
+			current:    "latest",
+			candidates: versions("1.31.1-do.0"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RecommendedUpgrade(tt.current, tt.candidates)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Slug)
+		})
+	}
+}
+
+func TestKubernetesVersion_SupportsFeature(t *testing.T) {
+	v := &KubernetesVersion{
+		Slug:              "1.30.2-do.0",
+		KubernetesVersion: "1.30.2",
+		SupportedFeatures: []string{KubernetesFeatureClusterAutoscaler, KubernetesFeatureDOCRIntegration},
+	}
+
+	assert.True(t, v.SupportsFeature(KubernetesFeatureClusterAutoscaler))
+	assert.True(t, v.SupportsFeature("docr-integration"))
+	assert.False(t, v.SupportsFeature(KubernetesFeatureHAControlPlane))
+	assert.False(t, (&KubernetesVersion{}).SupportsFeature(KubernetesFeatureClusterAutoscaler))
+}
+
+func TestKubernetesClusters_GetClusterSupportedFeatures(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "version": "1.30.2-do.0"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"options": {"versions": [
+			null,
+			{"slug": "1.31.1-do.0", "kubernetes_version": "1.31.1", "supported_features": ["cluster-autoscaler", "docr-integration", "ha-control-plane"]},
+			{"slug": "1.30.2-do.0", "kubernetes_version": "1.30.2", "supported_features": ["cluster-autoscaler", "docr-integration"]}
+		]}}`)
+	})
+
+	got, _, err := kubeSvc.GetClusterSupportedFeatures(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.NoError(t, err)
+	assert.Equal(t, []string{KubernetesFeatureClusterAutoscaler, KubernetesFeatureDOCRIntegration}, got)
+}
+
+func TestKubernetesClusters_GetClusterSupportedFeatures_UnknownVersion(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "version": "1.25.4-do.0"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/options", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"options": {"versions": [{"slug": "1.31.1-do.0"}]}}`)
+	})
+
+	_, _, err := kubeSvc.GetClusterSupportedFeatures(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1.25.4-do.0")
+}
+
+func TestKubernetesVersion_SupportWindow(t *testing.T) {
+	jBlob := `{"versions": [
+		{"slug": "1.31.1-do.0", "support_start": "2024-09-01", "support_end": "2025-10-28"},
+		{"slug": "1.30.5-do.0", "support_start": "2024-05-01T00:00:00Z", "support_end": "2025-06-28T00:00:00Z"},
+		{"slug": "1.29.9-do.0"},
+		{"slug": "1.28.2-do.0", "support_start": "soon", "support_end": "2024-10-01"}
+	]}`
+
+	var options KubernetesOptions
+	require.NoError(t, json.Unmarshal([]byte(jBlob), &options))
+	require.Len(t, options.Versions, 4)
+
+	start, end, ok := options.Versions[0].SupportWindow()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 10, 28, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, ok = options.Versions[1].SupportWindow()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, ok = options.Versions[2].SupportWindow()
+	assert.False(t, ok)
+
+	_, _, ok = options.Versions[3].SupportWindow()
+	assert.False(t, ok)
+}
+
+func TestKubernetesCluster_NodesOnOldVersion(t *testing.T) {
+	jBlob := `{
+		"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f",
+		"version": "1.31.1-do.0",
+		"node_pools": [
+			{"id": "pool-1", "nodes": [
+				{"id": "node-1", "kubelet_version": "v1.31.1"},
+				{"id": "node-2", "kubelet_version": "v1.30.5"}
+			]},
+			{"id": "pool-2", "nodes": [
+				{"id": "node-3", "kubelet_version": "1.29.9"},
+				{"id": "node-4"},
+				{"id": "node-5", "kubelet_version": "unknown"}
+			]}
+		]
+	}`
+
+	var cluster KubernetesCluster
+	require.NoError(t, json.Unmarshal([]byte(jBlob), &cluster))
+	assert.Equal(t, "v1.31.1", cluster.NodePools[0].Nodes[0].KubeletVersion)
+
+	old, ok := cluster.NodesOnOldVersion(cluster.VersionSlug)
+	require.True(t, ok)
+	var ids []string
+	for _, node := range old {
+		ids = append(ids, node.ID)
+	}
+	assert.Equal(t, []string{"node-2", "node-3"}, ids)
+
+	old, ok = cluster.NodesOnOldVersion("1.29.0-do.0")
+	assert.True(t, ok)
+	assert.Empty(t, old)
+
+	old, ok = cluster.NodesOnOldVersion("latest")
+	assert.False(t, ok)
+	assert.Nil(t, old)
+
+	// Without kubelet versions, the skew is unknown.
+	unknown := &KubernetesCluster{NodePools: []*KubernetesNodePool{{Nodes: []*KubernetesNode{{ID: "node-1"}}}}}
+	old, ok = unknown.NodesOnOldVersion("1.31.1-do.0")
+	assert.False(t, ok)
+	assert.Nil(t, old)
+}
+
+func TestKubernetesOptions_VersionsInRegion(t *testing.T) {
+	jBlob := `{
+		"versions": [
+			{"slug": "1.31.1-do.0", "regions": ["nyc1", "ams3"]},
+			{"slug": "1.30.5-do.0", "regions": ["nyc1"]},
+			{"slug": "1.29.9-do.0"}
+		],
+		"regions": [{"name": "New York 1", "slug": "nyc1"}, {"name": "Amsterdam 3", "slug": "ams3"}]
+	}`
+
+	var options KubernetesOptions
+	require.NoError(t, json.Unmarshal([]byte(jBlob), &options))
+
+	slugs := func(versions []*KubernetesVersion) []string {
+		var s []string
+		for _, v := range versions {
+			s = append(s, v.Slug)
+		}
+		return s
+	}
+	inRegion := func(region string) []string {
+		versions, ok := options.VersionsInRegion(region)
+		require.True(t, ok)
+		return slugs(versions)
+	}
+	assert.Equal(t, []string{"1.31.1-do.0", "1.30.5-do.0"}, inRegion("nyc1"))
+	assert.Equal(t, []string{"1.31.1-do.0"}, inRegion("ams3"))
+	assert.Empty(t, inRegion("sfo3"))
+
+	// Without region data, availability is unknown.
+	unknown := &KubernetesOptions{Versions: []*KubernetesVersion{{Slug: "1.31.1-do.0"}}}
+	versions, ok := unknown.VersionsInRegion("nyc1")
+	assert.False(t, ok)
+	assert.Empty(t, versions)
+}
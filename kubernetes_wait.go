@@ -0,0 +1,287 @@
+package godo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	defaultKubernetesWaitInterval = 10 * time.Second
+	defaultKubernetesWaitTimeout  = 30 * time.Minute
+)
+
+// KubernetesWaitOptions configures how the Kubernetes wait helpers poll the
+// API. A nil value or zero fields fall back to the defaults.
+type KubernetesWaitOptions struct {
+	// Interval is the time between two polls. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// Timeout bounds the total time spent waiting. Defaults to 30 minutes.
+	Timeout time.Duration
+
+	// Backoff multiplies Interval after every poll. Values of 1 or less,
+	// including the default, disable backoff.
+	Backoff float64
+}
+
+// withDefaults returns a copy of the options with the defaults applied to
+// every unset field. It is safe to call on a nil receiver.
+func (o *KubernetesWaitOptions) withDefaults() KubernetesWaitOptions {
+	var opts KubernetesWaitOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultKubernetesWaitInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultKubernetesWaitTimeout
+	}
+	if opts.Backoff < 1 {
+		opts.Backoff = 1
+	}
+	return opts
+}
+
+// nextInterval returns the time to wait after a poll that waited cur.
+func (o KubernetesWaitOptions) nextInterval(cur time.Duration) time.Duration {
+	return time.Duration(float64(cur) * o.Backoff)
+}
+
+// sleepContext waits for d or until ctx is done, whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitForClusterRunning polls the cluster until it reports the running state.
+// It gives up early if the cluster ends up in the error or deleted state.
+func (svc *KubernetesServiceOp) waitForClusterRunning(ctx context.Context, clusterID string, opts *KubernetesWaitOptions) (*KubernetesCluster, error) {
+	o := opts.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	for interval := o.Interval; ; interval = o.nextInterval(interval) {
+		cluster, _, err := svc.Get(ctx, clusterID)
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Status != nil {
+			switch cluster.Status.State {
+			case KubernetesClusterStatusRunning:
+				return cluster, nil
+			case KubernetesClusterStatusError, KubernetesClusterStatusDeleted:
+				return cluster, fmt.Errorf("cluster %s is in state %q: %s", clusterID, cluster.Status.State, cluster.Status.Message)
+			}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return cluster, err
+		}
+	}
+}
+
+// CreateAndVerify creates a Kubernetes cluster, waits for it to be running,
+// fetches its kubeconfig and hands it to probe. The cluster is only returned
+// without error once probe succeeds, which confirms the cluster is usable. If
+// any step after the creation fails, the cluster is returned alongside the
+// error so that callers can clean it up.
+func (svc *KubernetesServiceOp) CreateAndVerify(ctx context.Context, create *KubernetesClusterCreateRequest, probe func(*KubernetesClusterConfig) error, opts *KubernetesWaitOptions) (*KubernetesCluster, error) {
+	if probe == nil {
+		return nil, NewArgError("probe", "cannot be nil")
+	}
+
+	cluster, _, err := svc.Create(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := svc.waitForClusterRunning(ctx, cluster.ID, opts)
+	if running != nil {
+		cluster = running
+	}
+	if err != nil {
+		return cluster, err
+	}
+
+	config, _, err := svc.GetKubeConfig(ctx, cluster.ID)
+	if err != nil {
+		return cluster, err
+	}
+	if err := probe(config); err != nil {
+		return cluster, fmt.Errorf("probing cluster %s: %w", cluster.ID, err)
+	}
+	return cluster, nil
+}
+
+// RunClusterlintAndWait schedules a clusterlint run and polls for its results
+// until they are available, which the API signals by no longer answering with
+// 404 Not Found. As that answer is also given for unknown clusters, the
+// cluster is fetched once before polling, and an error matching
+// ErrKubernetesClusterNotFound is returned right away if it does not exist.
+// Polling stops when ctx is done or the timeout in opts expires.
+func (svc *KubernetesServiceOp) RunClusterlintAndWait(ctx context.Context, clusterID string, req *KubernetesRunClusterlintRequest, opts *KubernetesWaitOptions) ([]*ClusterlintDiagnostic, *Response, error) {
+	runID, resp, err := svc.RunClusterlint(ctx, clusterID, req)
+	if err != nil {
+		return nil, resp, err
+	}
+	if _, resp, err := svc.Get(ctx, clusterID); err != nil {
+		return nil, resp, err
+	}
+
+	o := opts.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	get := &KubernetesGetClusterlintRequest{RunId: runID}
+	for interval := o.Interval; ; interval = o.nextInterval(interval) {
+		diagnostics, resp, err := svc.GetClusterlintResults(ctx, clusterID, get)
+		if err == nil {
+			return diagnostics, resp, nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return nil, resp, err
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, resp, err
+		}
+	}
+}
+
+// DrainAndDeleteNode drains a node, deletes it and waits for the node that
+// replaces it to be running. The node is deleted with DeleteNode, asking the
+// API to drain it first and to replace it. The replacement is the first node
+// of the pool that did not exist before the deletion and reports the running
+// state; it is returned once found. Polling stops when ctx is done or the
+// timeout in opts expires, or with an error as soon as a new node is in the
+// error state and is not recoverable, see KubernetesNode.IsRecoverable.
+func (svc *KubernetesServiceOp) DrainAndDeleteNode(ctx context.Context, clusterID, poolID, nodeID string, opts *KubernetesWaitOptions) (*KubernetesNode, *Response, error) {
+	pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+	if err != nil {
+		return nil, resp, err
+	}
+	existing := make(map[string]bool, len(pool.Nodes))
+	for _, node := range pool.Nodes {
+		existing[node.ID] = true
+	}
+	if !existing[nodeID] {
+		return nil, resp, fmt.Errorf("node %s in node pool %s of cluster %s: %w", nodeID, poolID, clusterID, ErrKubernetesNodeNotFound)
+	}
+
+	resp, err = svc.DeleteNode(ctx, clusterID, poolID, nodeID, &KubernetesNodeDeleteRequest{Replace: true, SkipDrain: false})
+	if err != nil {
+		return nil, resp, err
+	}
+	return svc.waitForNodeReplacement(ctx, clusterID, poolID, existing, opts)
+}
+
+// waitForNodeReplacement polls the node pool until it has a running node whose
+// ID is not in existing. It fails if such a node errored permanently.
+func (svc *KubernetesServiceOp) waitForNodeReplacement(ctx context.Context, clusterID, poolID string, existing map[string]bool, opts *KubernetesWaitOptions) (*KubernetesNode, *Response, error) {
+	o := opts.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	for interval := o.Interval; ; interval = o.nextInterval(interval) {
+		pool, resp, err := svc.GetNodePool(ctx, clusterID, poolID)
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, node := range pool.Nodes {
+			if existing[node.ID] || node.Status == nil {
+				continue
+			}
+			if node.Status.State == "running" {
+				return node, resp, nil
+			}
+			if !node.IsRecoverable() {
+				return node, resp, fmt.Errorf("replacement node %s in node pool %s of cluster %s is in state %q: %s", node.ID, poolID, clusterID, node.Status.State, node.Status.Message)
+			}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, resp, err
+		}
+	}
+}
+
+// StreamClusterStatusMessages polls the status messages of a cluster and
+// emits every message it has not seen before on the returned channel, oldest
+// first. Each poll only asks for messages since the latest timestamp seen so
+// far; messages that share that timestamp are de-duplicated by their text.
+//
+// Streaming continues until ctx is done, at which point both channels are
+// closed. The Timeout in opts is ignored. If a poll fails, the error is sent on
+// the error channel and streaming stops.
+func (svc *KubernetesServiceOp) StreamClusterStatusMessages(ctx context.Context, clusterID string, opts *KubernetesWaitOptions) (<-chan *KubernetesClusterStatusMessage, <-chan error) {
+	messages := make(chan *KubernetesClusterStatusMessage)
+	errs := make(chan error, 1)
+	o := opts.withDefaults()
+
+	go func() {
+		defer close(messages)
+		defer close(errs)
+
+		var (
+			since  *time.Time
+			latest time.Time
+			// seen holds the messages that were emitted with the latest
+			// timestamp, which the next poll returns again.
+			seen = make(map[string]bool)
+		)
+		for interval := o.Interval; ; interval = o.nextInterval(interval) {
+			batch, _, err := svc.GetClusterStatusMessages(ctx, clusterID, &KubernetesGetClusterStatusMessagesRequest{Since: since})
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			sort.SliceStable(batch, func(i, j int) bool {
+				return batch[i].Timestamp.Before(batch[j].Timestamp)
+			})
+
+			for _, m := range batch {
+				switch {
+				case m.Timestamp.Before(latest):
+					continue
+				case m.Timestamp.Equal(latest):
+					if seen[m.Message] {
+						continue
+					}
+				default:
+					latest = m.Timestamp
+					seen = make(map[string]bool)
+				}
+				seen[m.Message] = true
+
+				select {
+				case messages <- m:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !latest.IsZero() {
+				t := latest
+				since = &t
+			}
+
+			if err := sleepContext(ctx, interval); err != nil {
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
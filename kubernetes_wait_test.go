@@ -0,0 +1,373 @@
+package godo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesClusters_CreateAndVerify(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "status": {"state": "provisioning"}}}`)
+	})
+
+	polls := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		polls++
+		state := "provisioning"
+		if polls > 1 {
+			state = "running"
+		}
+		fmt.Fprintf(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "status": {"state": %q}}}`, state)
+	})
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, "some YAML")
+	})
+
+	var probed []byte
+	probe := func(config *KubernetesClusterConfig) error {
+		probed = config.KubeconfigYAML
+		return nil
+	}
+
+	got, err := kubeSvc.CreateAndVerify(ctx, testKubernetesClusterCreateRequest(), probe, &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, KubernetesClusterStatusRunning, got.Status.State)
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, []byte("some YAML"), probed)
+}
+
+func TestKubernetesClusters_CreateAndVerify_ProbeFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "status": {"state": "running"}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/kubeconfig", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "some YAML")
+	})
+
+	errProbe := errors.New("no nodes")
+	got, err := kubeSvc.CreateAndVerify(ctx, testKubernetesClusterCreateRequest(), func(*KubernetesClusterConfig) error {
+		return errProbe
+	}, &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.ErrorIs(t, err, errProbe)
+	require.NotNil(t, got)
+	assert.Equal(t, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", got.ID)
+}
+
+func TestKubernetesClusters_CreateAndVerify_ClusterError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "status": {"state": "error", "message": "out of capacity"}}}`)
+	})
+
+	_, err := kubeSvc.CreateAndVerify(ctx, testKubernetesClusterCreateRequest(), func(*KubernetesClusterConfig) error {
+		t.Fatal("probe must not run for a failed cluster")
+		return nil
+	}, &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "out of capacity")
+}
+
+func testKubernetesClusterCreateRequest() *KubernetesClusterCreateRequest {
+	return &KubernetesClusterCreateRequest{
+		Name:        "antoine",
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.30.2-do.0",
+		NodePools: []*KubernetesNodePoolCreateRequest{
+			{Name: "pool-a", Size: "s-1vcpu-2gb", Count: 3},
+		},
+	}
+}
+
+func TestKubernetesWaitOptions_WithDefaults(t *testing.T) {
+	var nilOpts *KubernetesWaitOptions
+	assert.Equal(t, KubernetesWaitOptions{
+		Interval: 10 * time.Second,
+		Timeout:  30 * time.Minute,
+		Backoff:  1,
+	}, nilOpts.withDefaults())
+
+	partial := &KubernetesWaitOptions{Interval: time.Second, Backoff: 2}
+	assert.Equal(t, KubernetesWaitOptions{
+		Interval: time.Second,
+		Timeout:  30 * time.Minute,
+		Backoff:  2,
+	}, partial.withDefaults())
+	assert.Equal(t, &KubernetesWaitOptions{Interval: time.Second, Backoff: 2}, partial, "user options must not be modified")
+
+	assert.Equal(t, 4*time.Second, partial.withDefaults().nextInterval(2*time.Second))
+	assert.Equal(t, 2*time.Second, nilOpts.withDefaults().nextInterval(2*time.Second))
+}
+
+func TestKubernetesClusters_RunClusterlintAndWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	polls := 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/clusterlint", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			fmt.Fprint(w, `{"run_id": "1234"}`)
+		case http.MethodGet:
+			require.Equal(t, "run_id=1234", r.URL.Query().Encode())
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"id": "not_found", "message": "clusterlint run not complete"}`)
+				return
+			}
+			fmt.Fprint(w, `{"run_id": "1234", "diagnostics": [{"check_name": "unused-config-map", "severity": "warning", "message": "Unused config map"}]}`)
+		}
+	})
+
+	got, _, err := kubeSvc.RunClusterlintAndWait(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesRunClusterlintRequest{}, &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, []*ClusterlintDiagnostic{
+		{CheckName: "unused-config-map", Severity: "warning", Message: "Unused config map"},
+	}, got)
+}
+
+func TestKubernetesClusters_RunClusterlintAndWait_Timeout(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f"}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/clusterlint", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fmt.Fprint(w, `{"run_id": "1234"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := kubeSvc.RunClusterlintAndWait(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesRunClusterlintRequest{}, &KubernetesWaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	})
+	require.Error(t, err)
+}
+
+func TestKubernetesClusters_RunClusterlintAndWait_UnknownCluster(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"id": "not_found", "message": "The resource you requested could not be found."}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/clusterlint", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			fmt.Fprint(w, `{"run_id": "1234"}`)
+			return
+		}
+		t.Fatal("unexpected poll for the results of an unknown cluster")
+	})
+
+	_, _, err := kubeSvc.RunClusterlintAndWait(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesRunClusterlintRequest{}, &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.ErrorIs(t, err, ErrKubernetesClusterNotFound)
+}
+
+func TestKubernetesClusters_DrainAndDeleteNode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	deleted, polls := false, 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		switch {
+		case !deleted:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [
+				{"id": "node-1", "status": {"state": "running"}},
+				{"id": "node-2", "status": {"state": "running"}}
+			]}}`)
+		case polls == 0:
+			polls++
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [
+				{"id": "node-1", "status": {"state": "draining"}},
+				{"id": "node-2", "status": {"state": "running"}},
+				{"id": "node-3", "status": {"state": "provisioning"}}
+			]}}`)
+		default:
+			polls++
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [
+				{"id": "node-2", "status": {"state": "running"}},
+				{"id": "node-3", "name": "pool-a-new", "status": {"state": "running"}}
+			]}}`)
+		}
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a/nodes/node-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		assert.Equal(t, "replace=1", r.URL.RawQuery)
+		deleted = true
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	got, _, err := kubeSvc.DrainAndDeleteNode(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "node-1", &KubernetesWaitOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "node-3", got.ID)
+	assert.Equal(t, "pool-a-new", got.Name)
+	assert.Equal(t, 2, polls)
+}
+
+func TestKubernetesClusters_DrainAndDeleteNode_ReplacementFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	deleted, polls := false, 0
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		switch {
+		case !deleted:
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [{"id": "node-1", "status": {"state": "running"}}]}}`)
+		case polls == 0:
+			// A transient error is waited out.
+			polls++
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [
+				{"id": "node-3", "status": {"state": "error", "message": "timed out waiting for droplet"}}
+			]}}`)
+		default:
+			polls++
+			fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [
+				{"id": "node-3", "status": {"state": "error", "message": "droplet limit reached"}}
+			]}}`)
+		}
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a/nodes/node-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodDelete)
+		deleted = true
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	got, _, err := kubeSvc.DrainAndDeleteNode(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "node-1", &KubernetesWaitOptions{Interval: time.Millisecond, Timeout: time.Minute})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "droplet limit reached")
+	assert.Equal(t, "node-3", got.ID)
+	assert.Equal(t, 2, polls)
+}
+
+func TestKubernetesClusters_DrainAndDeleteNode_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools/8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"node_pool": {"id": "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "nodes": [{"id": "node-2"}]}}`)
+	})
+
+	_, _, err := kubeSvc.DrainAndDeleteNode(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "8d91899c-nodepool-4a1a-acc5-deadbeefbb8a", "node-1", nil)
+	require.ErrorIs(t, err, ErrKubernetesNodeNotFound)
+}
+
+func TestKubernetesClusters_StreamClusterStatusMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	var sinces []string
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/status_messages", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		since := r.URL.Query().Get("since")
+		sinces = append(sinces, since)
+		switch since {
+		case "":
+			fmt.Fprint(w, `{"messages": [
+				{"message": "provisioning nodes", "timestamp": "2018-06-15T07:11:00Z"},
+				{"message": "creating control plane", "timestamp": "2018-06-15T07:10:00Z"}
+			]}`)
+		case "2018-06-15T07:11:00Z":
+			fmt.Fprint(w, `{"messages": [
+				{"message": "provisioning nodes", "timestamp": "2018-06-15T07:11:00Z"},
+				{"message": "cluster is running", "timestamp": "2018-06-15T07:12:00Z"}
+			]}`)
+		default:
+			fmt.Fprint(w, `{"messages": [
+				{"message": "cluster is running", "timestamp": "2018-06-15T07:12:00Z"}
+			]}`)
+		}
+	})
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	messages, errs := kubeSvc.StreamClusterStatusMessages(streamCtx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesWaitOptions{Interval: time.Millisecond})
+
+	var got []string
+	for m := range messages {
+		got = append(got, m.Message)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"creating control plane", "provisioning nodes", "cluster is running"}, got)
+	require.GreaterOrEqual(t, len(sinces), 2)
+	assert.Equal(t, []string{"", "2018-06-15T07:11:00Z"}, sinces[:2])
+}
+
+func TestKubernetesClusters_StreamClusterStatusMessages_Error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	kubeSvc := client.Kubernetes
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/status_messages", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	messages, errs := kubeSvc.StreamClusterStatusMessages(ctx, "8d91899c-0739-4a1a-acc5-deadbeefbb8f", &KubernetesWaitOptions{Interval: time.Millisecond})
+	for range messages {
+		t.Fatal("unexpected message")
+	}
+	assert.Error(t, <-errs)
+}
@@ -0,0 +1,143 @@
+// Package promcollector exports the status of DigitalOcean Kubernetes clusters
+// as Prometheus metrics. It is a separate module so that the godo module does
+// not depend on the Prometheus client library.
+package promcollector
+
+import (
+	"context"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultTimeout bounds the API requests made by a single collection.
+const defaultTimeout = 30 * time.Second
+
+// nodePoolsPerPage is the page size used to list node pools, the largest the
+// API accepts.
+const nodePoolsPerPage = 200
+
+var (
+	clusterStateDesc = prometheus.NewDesc(
+		"godo_k8s_cluster_state",
+		"State of the Kubernetes cluster; 1 for the current state.",
+		[]string{"cluster_id", "cluster_name", "state"}, nil,
+	)
+	nodePoolCountDesc = prometheus.NewDesc(
+		"godo_k8s_nodepool_count",
+		"Number of nodes of the node pool.",
+		[]string{"cluster_id", "node_pool"}, nil,
+	)
+	nodeReadyDesc = prometheus.NewDesc(
+		"godo_k8s_node_ready",
+		"Whether the node is running; 1 if it is, 0 otherwise.",
+		[]string{"cluster_id", "node_pool", "node"}, nil,
+	)
+)
+
+// KubernetesStatusCollector is a prometheus.Collector that reports the state
+// of Kubernetes clusters, the node counts of their node pools and the
+// readiness of their nodes. The clusters are fetched from the API on every
+// collection.
+type KubernetesStatusCollector struct {
+	// Timeout bounds the API requests made by a single collection. Defaults
+	// to 30 seconds.
+	Timeout time.Duration
+
+	client     *godo.Client
+	clusterIDs []string
+}
+
+var _ prometheus.Collector = (*KubernetesStatusCollector)(nil)
+
+// NewKubernetesStatusCollector returns a collector for the given clusters.
+func NewKubernetesStatusCollector(client *godo.Client, clusterIDs []string) *KubernetesStatusCollector {
+	return &KubernetesStatusCollector{
+		client:     client,
+		clusterIDs: append([]string(nil), clusterIDs...),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *KubernetesStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterStateDesc
+	ch <- nodePoolCountDesc
+	ch <- nodeReadyDesc
+}
+
+// Collect implements prometheus.Collector. Clusters that cannot be fetched are
+// reported as invalid metrics, which makes the scrape fail for them.
+func (c *KubernetesStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, id := range c.clusterIDs {
+		c.collectCluster(ctx, ch, id)
+	}
+}
+
+// collectCluster emits the metrics of a single cluster.
+func (c *KubernetesStatusCollector) collectCluster(ctx context.Context, ch chan<- prometheus.Metric, clusterID string) {
+	cluster, _, err := c.client.Kubernetes.Get(ctx, clusterID)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(clusterStateDesc, err)
+		return
+	}
+	var state godo.KubernetesClusterStatusState
+	if cluster.Status != nil {
+		state = cluster.Status.State
+	}
+	ch <- prometheus.MustNewConstMetric(clusterStateDesc, prometheus.GaugeValue, 1, cluster.ID, cluster.Name, string(state))
+
+	pools, err := c.listNodePools(ctx, clusterID)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(nodePoolCountDesc, err)
+		return
+	}
+	for _, pool := range pools {
+		ch <- prometheus.MustNewConstMetric(nodePoolCountDesc, prometheus.GaugeValue, float64(pool.Count), clusterID, pool.Name)
+		for _, node := range pool.Nodes {
+			ready := 0.0
+			if node.Status != nil && node.Status.State == "running" {
+				ready = 1
+			}
+			ch <- prometheus.MustNewConstMetric(nodeReadyDesc, prometheus.GaugeValue, ready, clusterID, pool.Name, node.Name)
+		}
+	}
+}
+
+// listNodePools returns all node pools of the cluster, going through all pages.
+// Responses without pagination links end the listing once a page is not full.
+func (c *KubernetesStatusCollector) listNodePools(ctx context.Context, clusterID string) ([]*godo.KubernetesNodePool, error) {
+	opts := &godo.ListOptions{Page: 1, PerPage: nodePoolsPerPage}
+
+	var all []*godo.KubernetesNodePool
+	for {
+		pools, resp, err := c.client.Kubernetes.ListNodePools(ctx, clusterID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, pools...)
+
+		if resp.Links == nil {
+			if len(pools) < opts.PerPage {
+				return all, nil
+			}
+			opts.Page++
+			continue
+		}
+		if resp.Links.IsLastPage() {
+			return all, nil
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return nil, err
+		}
+		opts.Page = page + 1
+	}
+}
@@ -0,0 +1,103 @@
+package promcollector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesStatusCollector(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "prod", "status": {"state": "running"}}}`)
+	})
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"node_pools": [{"id": "pool-1", "name": "workers", "count": 2, "nodes": [
+			{"id": "node-1", "name": "workers-1", "status": {"state": "running"}},
+			{"id": "node-2", "name": "workers-2", "status": {"state": "provisioning"}}
+		]}]}`)
+	})
+
+	client, err := godo.New(nil, godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(NewKubernetesStatusCollector(client, []string{"8d91899c-0739-4a1a-acc5-deadbeefbb8f"})))
+
+	expected := `
+# HELP godo_k8s_cluster_state State of the Kubernetes cluster; 1 for the current state.
+# TYPE godo_k8s_cluster_state gauge
+godo_k8s_cluster_state{cluster_id="8d91899c-0739-4a1a-acc5-deadbeefbb8f",cluster_name="prod",state="running"} 1
+# HELP godo_k8s_node_ready Whether the node is running; 1 if it is, 0 otherwise.
+# TYPE godo_k8s_node_ready gauge
+godo_k8s_node_ready{cluster_id="8d91899c-0739-4a1a-acc5-deadbeefbb8f",node="workers-1",node_pool="workers"} 1
+godo_k8s_node_ready{cluster_id="8d91899c-0739-4a1a-acc5-deadbeefbb8f",node="workers-2",node_pool="workers"} 0
+# HELP godo_k8s_nodepool_count Number of nodes of the node pool.
+# TYPE godo_k8s_nodepool_count gauge
+godo_k8s_nodepool_count{cluster_id="8d91899c-0739-4a1a-acc5-deadbeefbb8f",node_pool="workers"} 2
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected)))
+}
+
+func TestKubernetesStatusCollector_NodePoolPages(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kubernetes_cluster": {"id": "8d91899c-0739-4a1a-acc5-deadbeefbb8f", "name": "prod", "status": {"state": "running"}}}`)
+	})
+	var pages []string
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f/node_pools", func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		assert.Equal(t, fmt.Sprint(nodePoolsPerPage), r.URL.Query().Get("per_page"))
+
+		count := nodePoolsPerPage
+		if page == "2" {
+			count = 1
+		}
+		pools := make([]string, count)
+		for i := range pools {
+			pools[i] = fmt.Sprintf(`{"id": "pool-%[1]s-%[2]d", "name": "pool-%[1]s-%[2]d", "count": 1}`, page, i)
+		}
+		fmt.Fprintf(w, `{"node_pools": [%s]}`, strings.Join(pools, ","))
+	})
+
+	client, err := godo.New(nil, godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+
+	collector := NewKubernetesStatusCollector(client, []string{"8d91899c-0739-4a1a-acc5-deadbeefbb8f"})
+	require.Equal(t, 1+nodePoolsPerPage+1, testutil.CollectAndCount(collector))
+	require.Equal(t, []string{"1", "2"}, pages)
+}
+
+func TestKubernetesStatusCollector_Error(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/kubernetes/clusters/8d91899c-0739-4a1a-acc5-deadbeefbb8f", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	client, err := godo.New(nil, godo.SetBaseURL(server.URL))
+	require.NoError(t, err)
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(NewKubernetesStatusCollector(client, []string{"8d91899c-0739-4a1a-acc5-deadbeefbb8f"})))
+
+	_, err = registry.Gather()
+	require.Error(t, err)
+}